@@ -0,0 +1,157 @@
+// Package filters has probabilistic membership filters. They can tell for sure that an element was never added,
+// but may report that an element was added when it was not, with a configurable false positive rate.
+// They are useful to avoid expensive lookups of elements that do not exist.
+package filters
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+
+	"github.com/totemcaf/gollections/hashes"
+	"github.com/totemcaf/gollections/internal/binenc"
+)
+
+const formatVersion = 1
+
+var (
+	errIncompatibleFilters = errors.New("filters have different sizes")
+	errInvalidEncoding     = errors.New("invalid filter encoding")
+)
+
+// BloomFilter is a probabilistic set that can only grow. It uses a fixed number of bits computed from the expected
+// number of elements and the desired false positive rate.
+type BloomFilter[T comparable] struct {
+	words  []uint64
+	size   uint64 // number of bits
+	hashes uint64 // number of bits set for each element
+	hasher hashes.Hasher[T]
+}
+
+// NewBloomFilter creates a filter for the expected number of elements with the given false positive rate
+// (between 0 and 1). If hasher is nil, hashes.For is used. Adding more elements than expected increases the rate.
+func NewBloomFilter[T comparable](expected int, falsePositiveRate float64, hasher hashes.Hasher[T]) *BloomFilter[T] {
+	if expected < 1 {
+		expected = 1
+	}
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		panic("false positive rate must be between 0 and 1")
+	}
+
+	size := uint64(math.Ceil(-float64(expected) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	count := uint64(math.Round(float64(size) / float64(expected) * math.Ln2))
+	if count < 1 {
+		count = 1
+	}
+
+	f := &BloomFilter[T]{hasher: hasher}
+	f.init(size, count)
+	return f
+}
+
+func (f *BloomFilter[T]) init(size, count uint64) {
+	f.words = make([]uint64, (size+63)/64)
+	f.size = size
+	f.hashes = count
+	if f.hasher == nil {
+		f.hasher = hashes.For[T]()
+	}
+}
+
+// positions calls fn with each bit of the value, using double hashing to derive them from a single hash
+func (f *BloomFilter[T]) positions(value T, fn func(word int, mask uint64) bool) bool {
+	h1 := f.hasher(value)
+	h2 := hashes.Mix(h1) | 1
+
+	for i := uint64(0); i < f.hashes; i++ {
+		position := (h1 + i*h2) % f.size
+		if !fn(int(position/64), 1<<(position%64)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Add adds the value to the filter
+func (f *BloomFilter[T]) Add(value T) {
+	f.positions(value, func(word int, mask uint64) bool {
+		f.words[word] |= mask
+		return true
+	})
+}
+
+// MayContain returns false if the value was never added, or true if it probably was
+func (f *BloomFilter[T]) MayContain(value T) bool {
+	return f.positions(value, func(word int, mask uint64) bool {
+		return f.words[word]&mask != 0
+	})
+}
+
+// ApproximateCount estimates the number of distinct values added from the number of bits set
+func (f *BloomFilter[T]) ApproximateCount() int {
+	set := 0
+	for _, w := range f.words {
+		set += bits.OnesCount64(w)
+	}
+	if uint64(set) == f.size {
+		return math.MaxInt
+	}
+	m, k := float64(f.size), float64(f.hashes)
+	return int(math.Round(-m / k * math.Log(1-float64(set)/m)))
+}
+
+// Merge adds all the values of the other filter to this one. Both filters must have been created with the same
+// expected count and false positive rate, and use the same hasher.
+func (f *BloomFilter[T]) Merge(other *BloomFilter[T]) error {
+	if f.size != other.size || f.hashes != other.hashes {
+		return errIncompatibleFilters
+	}
+	for idx, w := range other.words {
+		f.words[idx] |= w
+	}
+	return nil
+}
+
+// Clear removes all the values from the filter
+func (f *BloomFilter[T]) Clear() {
+	for idx := range f.words {
+		f.words[idx] = 0
+	}
+}
+
+// MarshalBinary encodes the filter as a version byte, the number of bits and of hashes as varints and the bits as
+// little endian words. The hasher is not encoded, the filter must be read with the same one.
+func (f *BloomFilter[T]) MarshalBinary() ([]byte, error) {
+	data := []byte{formatVersion}
+	data = binenc.AppendUvarint(data, f.size)
+	data = binenc.AppendUvarint(data, f.hashes)
+	for _, w := range f.words {
+		data = binenc.AppendUint64(data, w)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes a filter written by MarshalBinary. If the filter has no hasher, hashes.For is used.
+func (f *BloomFilter[T]) UnmarshalBinary(data []byte) error {
+	reader := binenc.NewReader(data, errInvalidEncoding)
+
+	version := reader.Byte()
+	size := reader.Uvarint()
+	count := reader.Uvarint()
+	if reader.Err() != nil || version != formatVersion || size == 0 || count == 0 || size > uint64(len(data))*8 {
+		return errInvalidEncoding
+	}
+
+	words := make([]uint64, (size+63)/64)
+	for idx := range words {
+		words[idx] = reader.Uint64()
+	}
+	if err := reader.Finish(); err != nil {
+		return err
+	}
+
+	f.init(size, count)
+	f.words = words
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package filters
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func falsePositives(mayContain func(string) bool, from, to int) float64 {
+	count := 0
+	for i := from; i < to; i++ {
+		if mayContain("key-" + strconv.Itoa(i)) {
+			count++
+		}
+	}
+	return float64(count) / float64(to-from)
+}
+
+func TestBloomFilter_has_no_false_negatives(t *testing.T) {
+	f := NewBloomFilter[string](10000, 0.01, nil)
+	for i := 0; i < 10000; i++ {
+		f.Add("key-" + strconv.Itoa(i))
+	}
+
+	assert.Equal(t, 0.0, 1-falsePositives(f.MayContain, 0, 10000))
+	assert.Less(t, falsePositives(f.MayContain, 10000, 110000), 0.02)
+	assert.InDelta(t, 10000, f.ApproximateCount(), 300)
+}
+
+func TestBloomFilter_Merge(t *testing.T) {
+	a := NewBloomFilter[int](100, 0.01, nil)
+	b := NewBloomFilter[int](100, 0.01, nil)
+	a.Add(1)
+	b.Add(2)
+
+	assert.Nil(t, a.Merge(b))
+	assert.True(t, a.MayContain(1))
+	assert.True(t, a.MayContain(2))
+	assert.Error(t, a.Merge(NewBloomFilter[int](1000, 0.01, nil)))
+}
+
+func TestBloomFilter_binary_encoding(t *testing.T) {
+	f := NewBloomFilter[string](1000, 0.001, nil)
+	f.Add("a")
+	f.Add("b")
+
+	data, err := f.MarshalBinary()
+	assert.Nil(t, err)
+
+	var read BloomFilter[string]
+	assert.Nil(t, read.UnmarshalBinary(data))
+	assert.True(t, read.MayContain("a"))
+	assert.True(t, read.MayContain("b"))
+	assert.False(t, read.MayContain("c"))
+
+	assert.Error(t, read.UnmarshalBinary(data[:len(data)-1]))
+}
+
+func TestBloomFilter_custom_hasher(t *testing.T) {
+	type user struct {
+		ID   int
+		Name string
+	}
+	byID := func(u user) uint64 { return uint64(u.ID) * 0x9e3779b97f4a7c15 }
+
+	f := NewBloomFilter[user](100, 0.01, byID)
+	f.Add(user{ID: 1, Name: "Ann"})
+
+	assert.True(t, f.MayContain(user{ID: 1, Name: "renamed"}))
+}
@@ -0,0 +1,261 @@
+package filters
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+
+	"github.com/totemcaf/gollections/hashes"
+	"github.com/totemcaf/gollections/internal/binenc"
+)
+
+const (
+	bucketSize = 4
+	maxKicks   = 500
+)
+
+var errFilterFull = errors.New("cuckoo filter is full")
+
+// CuckooFilter is a probabilistic set that supports removing values. It keeps a small fingerprint of each value in
+// one of two possible buckets, moving fingerprints between their buckets to make room when needed.
+type CuckooFilter[T comparable] struct {
+	buckets []uint16 // bucketSize fingerprints per bucket, 0 is an empty slot
+	mask    uint64   // number of buckets - 1, the number of buckets is a power of 2
+	bits    uint     // bits of each fingerprint
+	count   int
+	hasher  hashes.Hasher[T]
+}
+
+// kick is a fingerprint evicted from a slot
+type kick struct {
+	slot        int
+	fingerprint uint16
+}
+
+// NewCuckooFilter creates a filter for the expected number of elements with the given false positive rate
+// (between 0 and 1). If hasher is nil, hashes.For is used. Fingerprints have at most 16 bits, so rates below
+// 0.0002 are not reached.
+func NewCuckooFilter[T comparable](expected int, falsePositiveRate float64, hasher hashes.Hasher[T]) *CuckooFilter[T] {
+	if expected < 1 {
+		expected = 1
+	}
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		panic("false positive rate must be between 0 and 1")
+	}
+
+	// with 95% load a lookup checks 2 buckets, so it compares up to 8 fingerprints
+	fingerprintBits := uint(math.Ceil(math.Log2(2 * bucketSize / falsePositiveRate)))
+	if fingerprintBits < 4 {
+		fingerprintBits = 4
+	}
+	if fingerprintBits > 16 {
+		fingerprintBits = 16
+	}
+
+	buckets := uint64(1)
+	for float64(buckets*bucketSize)*0.95 < float64(expected) {
+		buckets *= 2
+	}
+
+	f := &CuckooFilter[T]{hasher: hasher}
+	f.init(buckets, fingerprintBits)
+	return f
+}
+
+func (f *CuckooFilter[T]) init(buckets uint64, fingerprintBits uint) {
+	f.buckets = make([]uint16, buckets*bucketSize)
+	f.mask = buckets - 1
+	f.bits = fingerprintBits
+	f.count = 0
+	if f.hasher == nil {
+		f.hasher = hashes.For[T]()
+	}
+}
+
+// locate returns the fingerprint of the value and its first bucket
+func (f *CuckooFilter[T]) locate(value T) (uint16, uint64) {
+	h := f.hasher(value)
+	fingerprint := uint16(hashes.Mix(h) >> (64 - f.bits))
+	if fingerprint == 0 {
+		fingerprint = 1
+	}
+	return fingerprint, h & f.mask
+}
+
+// alternate returns the other bucket of a fingerprint. It is its own inverse, so it works from both buckets.
+func (f *CuckooFilter[T]) alternate(bucket uint64, fingerprint uint16) uint64 {
+	return (bucket ^ hashes.Uint64(uint64(fingerprint))) & f.mask
+}
+
+func (f *CuckooFilter[T]) slots(bucket uint64) []uint16 {
+	return f.buckets[bucket*bucketSize : (bucket+1)*bucketSize]
+}
+
+func (f *CuckooFilter[T]) insertAt(bucket uint64, fingerprint uint16) bool {
+	slots := f.slots(bucket)
+	for idx, fp := range slots {
+		if fp == 0 {
+			slots[idx] = fingerprint
+			return true
+		}
+	}
+	return false
+}
+
+func (f *CuckooFilter[T]) insert(bucket uint64, fingerprint uint16) error {
+	alternate := f.alternate(bucket, fingerprint)
+	if f.insertAt(bucket, fingerprint) || f.insertAt(alternate, fingerprint) {
+		f.count++
+		return nil
+	}
+
+	if rand.Intn(2) == 0 {
+		bucket = alternate
+	}
+
+	// evict fingerprints to their other bucket, remembering the moves to undo them if there is no room
+	var path []kick
+	for n := 0; n < maxKicks; n++ {
+		slot := int(bucket)*bucketSize + rand.Intn(bucketSize)
+		path = append(path, kick{slot, f.buckets[slot]})
+		fingerprint, f.buckets[slot] = f.buckets[slot], fingerprint
+
+		bucket = f.alternate(bucket, fingerprint)
+		if f.insertAt(bucket, fingerprint) {
+			f.count++
+			return nil
+		}
+	}
+
+	for idx := len(path) - 1; idx >= 0; idx-- {
+		f.buckets[path[idx].slot] = path[idx].fingerprint
+	}
+
+	return errFilterFull
+}
+
+// Add adds the value to the filter. It fails if the filter is full, in which case the filter is not modified.
+// Adding a value more than once stores it more than once, so it must be deleted as many times.
+func (f *CuckooFilter[T]) Add(value T) error {
+	fingerprint, bucket := f.locate(value)
+	return f.insert(bucket, fingerprint)
+}
+
+// MayContain returns false if the value is not in the filter, or true if it probably is
+func (f *CuckooFilter[T]) MayContain(value T) bool {
+	fingerprint, bucket := f.locate(value)
+	return f.find(bucket, fingerprint) >= 0 || f.find(f.alternate(bucket, fingerprint), fingerprint) >= 0
+}
+
+func (f *CuckooFilter[T]) find(bucket uint64, fingerprint uint16) int {
+	for idx, fp := range f.slots(bucket) {
+		if fp == fingerprint {
+			return int(bucket)*bucketSize + idx
+		}
+	}
+	return -1
+}
+
+// Delete removes the value from the filter and returns true, or returns false if it is not in the filter.
+// Only values that were added must be deleted, otherwise another value with the same fingerprint could be removed.
+func (f *CuckooFilter[T]) Delete(value T) bool {
+	fingerprint, bucket := f.locate(value)
+
+	slot := f.find(bucket, fingerprint)
+	if slot < 0 {
+		slot = f.find(f.alternate(bucket, fingerprint), fingerprint)
+	}
+	if slot < 0 {
+		return false
+	}
+
+	f.buckets[slot] = 0
+	f.count--
+	return true
+}
+
+// Count returns the number of values in the filter
+func (f *CuckooFilter[T]) Count() int {
+	return f.count
+}
+
+// LoadFactor returns the fraction of used slots
+func (f *CuckooFilter[T]) LoadFactor() float64 {
+	return float64(f.count) / float64(len(f.buckets))
+}
+
+// Merge adds all the values of the other filter to this one. Both filters must have been created with the same
+// expected count and false positive rate, and use the same hasher. If this filter becomes full, it is not modified.
+func (f *CuckooFilter[T]) Merge(other *CuckooFilter[T]) error {
+	if f.mask != other.mask || f.bits != other.bits {
+		return errIncompatibleFilters
+	}
+
+	merged := *f
+	merged.buckets = make([]uint16, len(f.buckets))
+	copy(merged.buckets, f.buckets)
+
+	for slot, fingerprint := range other.buckets {
+		if fingerprint == 0 {
+			continue
+		}
+		if err := merged.insert(uint64(slot/bucketSize), fingerprint); err != nil {
+			return err
+		}
+	}
+
+	*f = merged
+	return nil
+}
+
+// Clear removes all the values from the filter
+func (f *CuckooFilter[T]) Clear() {
+	for idx := range f.buckets {
+		f.buckets[idx] = 0
+	}
+	f.count = 0
+}
+
+// MarshalBinary encodes the filter as a version byte, the number of buckets and the bits of the fingerprints as
+// varints and the fingerprints as little endian 16 bits numbers. The hasher is not encoded, the filter must be read
+// with the same one.
+func (f *CuckooFilter[T]) MarshalBinary() ([]byte, error) {
+	data := []byte{formatVersion}
+	data = binenc.AppendUvarint(data, f.mask+1)
+	data = binenc.AppendUvarint(data, uint64(f.bits))
+	for _, fp := range f.buckets {
+		data = binenc.AppendUint16(data, fp)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes a filter written by MarshalBinary. If the filter has no hasher, hashes.For is used.
+func (f *CuckooFilter[T]) UnmarshalBinary(data []byte) error {
+	reader := binenc.NewReader(data, errInvalidEncoding)
+
+	version := reader.Byte()
+	buckets := reader.Uvarint()
+	fingerprintBits := reader.Uvarint()
+	if reader.Err() != nil || version != formatVersion || buckets == 0 || buckets&(buckets-1) != 0 ||
+		buckets > uint64(len(data)) || fingerprintBits < 1 || fingerprintBits > 16 {
+		return errInvalidEncoding
+	}
+
+	fingerprints := make([]uint16, buckets*bucketSize)
+	count := 0
+	for idx := range fingerprints {
+		fingerprints[idx] = reader.Uint16()
+		if fingerprints[idx] != 0 {
+			count++
+		}
+	}
+	if err := reader.Finish(); err != nil {
+		return err
+	}
+
+	f.init(buckets, uint(fingerprintBits))
+	f.buckets = fingerprints
+	f.count = count
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package filters
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCuckooFilter_has_no_false_negatives(t *testing.T) {
+	f := NewCuckooFilter[string](10000, 0.01, nil)
+	for i := 0; i < 10000; i++ {
+		assert.Nil(t, f.Add("key-"+strconv.Itoa(i)))
+	}
+
+	assert.Equal(t, 10000, f.Count())
+	assert.Equal(t, 0.0, 1-falsePositives(f.MayContain, 0, 10000))
+	assert.Less(t, falsePositives(f.MayContain, 10000, 110000), 0.02)
+}
+
+func TestCuckooFilter_Delete(t *testing.T) {
+	f := NewCuckooFilter[string](100, 0.01, nil)
+	assert.Nil(t, f.Add("a"))
+	assert.Nil(t, f.Add("b"))
+
+	assert.True(t, f.Delete("a"))
+	assert.False(t, f.Delete("a"))
+	assert.False(t, f.MayContain("a"))
+	assert.True(t, f.MayContain("b"))
+	assert.Equal(t, 1, f.Count())
+}
+
+func TestCuckooFilter_full_filter_is_not_modified(t *testing.T) {
+	f := NewCuckooFilter[int](8, 0.01, nil)
+
+	added := 0
+	var err error
+	for err == nil {
+		if err = f.Add(added); err == nil {
+			added++
+		}
+	}
+
+	assert.ErrorContains(t, err, "full")
+	assert.Equal(t, added, f.Count())
+	for i := 0; i < added; i++ {
+		assert.True(t, f.MayContain(i))
+	}
+}
+
+func TestCuckooFilter_Merge(t *testing.T) {
+	a := NewCuckooFilter[int](100, 0.01, nil)
+	b := NewCuckooFilter[int](100, 0.01, nil)
+	assert.Nil(t, a.Add(1))
+	assert.Nil(t, b.Add(2))
+
+	assert.Nil(t, a.Merge(b))
+	assert.True(t, a.MayContain(1))
+	assert.True(t, a.MayContain(2))
+	assert.Equal(t, 2, a.Count())
+	assert.Error(t, a.Merge(NewCuckooFilter[int](1000, 0.01, nil)))
+}
+
+func TestCuckooFilter_binary_encoding(t *testing.T) {
+	f := NewCuckooFilter[string](1000, 0.001, nil)
+	assert.Nil(t, f.Add("a"))
+
+	data, err := f.MarshalBinary()
+	assert.Nil(t, err)
+
+	var read CuckooFilter[string]
+	assert.Nil(t, read.UnmarshalBinary(data))
+	assert.True(t, read.MayContain("a"))
+	assert.Equal(t, 1, read.Count())
+	assert.True(t, read.Delete("a"))
+
+	assert.Error(t, read.UnmarshalBinary(data[:len(data)-1]))
+}
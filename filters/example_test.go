@@ -0,0 +1,42 @@
+package filters
+
+import (
+	"fmt"
+
+	"github.com/totemcaf/gollections/repositories"
+)
+
+type product struct {
+	Sku  string
+	Name string
+}
+
+// Shows how to avoid looking up keys that are not in a repository
+func Example_filter_in_front_of_repository() {
+	repo := &repositories.InMemoryRepository[string, *product]{GetKey: func(p *product) string { return p.Sku }}
+	known := NewBloomFilter[string](1000, 0.01, nil)
+
+	for _, p := range []*product{{"A-1", "Chair"}, {"B-2", "Table"}} {
+		if _, err := repo.Create(p); err == nil {
+			known.Add(p.Sku)
+		}
+	}
+
+	find := func(sku string) (*product, bool) {
+		if !known.MayContain(sku) {
+			return nil, false
+		}
+		p, err := repo.FindByID(sku)
+		return p, err == nil
+	}
+
+	p, found := find("B-2")
+	fmt.Println(p.Name, found)
+
+	_, found = find("Z-9")
+	fmt.Println(found)
+
+	// Output:
+	// Table true
+	// false
+}
@@ -2,11 +2,12 @@ module github.com/totemcaf/gollections
 
 go 1.18
 
+require golang.org/x/exp v0.0.0-20231006140011-7918f672742d
+
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	// github.com/stretchr/objx v0.4.0 // indirect
-	github.com/stretchr/testify v1.8.0 // indirect
-	golang.org/x/exp v0.0.0-20231006140011-7918f672742d // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
+	github.com/stretchr/testify v1.8.0
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,141 @@
+package hashes
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+)
+
+// Hasher computes a 64 bits hash of a value. Equal values must have the same hash.
+type Hasher[T any] func(T) uint64
+
+const (
+	offset64 = 14695981039346656037
+	prime64  = 1099511628211
+)
+
+// For returns a Hasher for any comparable type.
+// Hashes are stable between processes for values made of numbers, strings and booleans, so they can be used in
+// serialized structures. Pointers and channels are hashed by identity. Interfaces are hashed by the type and the
+// value they hold.
+func For[T comparable]() Hasher[T] {
+	return Of[T]
+}
+
+// Of returns the hash of a comparable value. See For.
+func Of[T comparable](value T) uint64 {
+	switch v := any(value).(type) {
+	case string:
+		return String(v)
+	case int:
+		return Uint64(uint64(v))
+	case int64:
+		return Uint64(uint64(v))
+	case int32:
+		return Uint64(uint64(v))
+	case uint:
+		return Uint64(uint64(v))
+	case uint64:
+		return Uint64(v)
+	case uint32:
+		return Uint64(uint64(v))
+	}
+
+	h := hasher{offset64}
+	h.value(reflect.ValueOf(&value).Elem())
+	return Mix(h.sum)
+}
+
+// String returns the hash of a string
+func String(s string) uint64 {
+	h := hasher{offset64}
+	h.string(s)
+	return Mix(h.sum)
+}
+
+// Uint64 returns the hash of a number
+func Uint64(n uint64) uint64 {
+	return Mix(n ^ offset64)
+}
+
+// Mix scrambles the bits of a hash (the splitmix64 finalizer), so similar inputs produce very different outputs.
+// It is useful to derive more hashes from a single one.
+func Mix(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
+
+// hasher is a FNV-1a hash fed with the content of values
+type hasher struct {
+	sum uint64
+}
+
+func (h *hasher) byte(b byte) {
+	h.sum ^= uint64(b)
+	h.sum *= prime64
+}
+
+func (h *hasher) uint64(n uint64) {
+	var buffer [8]byte
+	binary.LittleEndian.PutUint64(buffer[:], n)
+	for _, b := range buffer {
+		h.byte(b)
+	}
+}
+
+func (h *hasher) string(s string) {
+	h.uint64(uint64(len(s)))
+	for idx := 0; idx < len(s); idx++ {
+		h.byte(s[idx])
+	}
+}
+
+func (h *hasher) float(f float64) {
+	if f == 0 {
+		f = 0 // +0 and -0 are equal, so they must have the same hash
+	}
+	h.uint64(math.Float64bits(f))
+}
+
+func (h *hasher) value(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			h.byte(1)
+		} else {
+			h.byte(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		h.uint64(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		h.uint64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		h.float(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		h.float(real(v.Complex()))
+		h.float(imag(v.Complex()))
+	case reflect.String:
+		h.string(v.String())
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		h.uint64(uint64(v.Pointer()))
+	case reflect.Array:
+		for idx := 0; idx < v.Len(); idx++ {
+			h.value(v.Index(idx))
+		}
+	case reflect.Struct:
+		for idx := 0; idx < v.NumField(); idx++ {
+			h.value(v.Field(idx))
+		}
+	case reflect.Interface:
+		if v.IsNil() {
+			h.byte(0)
+			return
+		}
+		h.string(v.Elem().Type().String())
+		h.value(v.Elem())
+	}
+}
@@ -0,0 +1,32 @@
+package hashes
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type point struct {
+	X, Y  int
+	label string
+}
+
+func TestOf_equal_values_have_equal_hashes(t *testing.T) {
+	assert.Equal(t, Of("hello"), Of("hel"+"lo"))
+	assert.Equal(t, Of(point{1, 2, "a"}), Of(point{1, 2, "a"}))
+	assert.Equal(t, Of(0.0), Of(math.Copysign(0, -1)))
+}
+
+func TestOf_different_values_have_different_hashes(t *testing.T) {
+	assert.NotEqual(t, Of(point{1, 2, "a"}), Of(point{2, 1, "a"}))
+	assert.NotEqual(t, Of(point{1, 2, "a"}), Of(point{1, 2, "b"}))
+	assert.NotEqual(t, Of([2]string{"ab", "c"}), Of([2]string{"a", "bc"}))
+	assert.NotEqual(t, Of(1), Of(2))
+}
+
+func TestOf_is_stable(t *testing.T) {
+	// Serialized structures depend on hashes not changing between versions
+	assert.Equal(t, uint64(0xab78224cc3df5888), Of("gollections"))
+	assert.Equal(t, uint64(0x4e43d7f82e037c16), Of(42))
+}
@@ -0,0 +1,109 @@
+// Package binenc has helpers to write and read the portable binary formats of the collections. Fixed size numbers
+// are little endian.
+package binenc
+
+import "encoding/binary"
+
+// AppendUvarint appends n as a varint
+func AppendUvarint(data []byte, n uint64) []byte {
+	var buffer [binary.MaxVarintLen64]byte
+	return append(data, buffer[:binary.PutUvarint(buffer[:], n)]...)
+}
+
+// AppendUint16 appends n as 2 bytes
+func AppendUint16(data []byte, n uint16) []byte {
+	return append(data, byte(n), byte(n>>8))
+}
+
+// AppendUint64 appends n as 8 bytes
+func AppendUint64(data []byte, n uint64) []byte {
+	var buffer [8]byte
+	binary.LittleEndian.PutUint64(buffer[:], n)
+	return append(data, buffer[:]...)
+}
+
+// Reader reads numbers from data. On the first failure it remembers the error and the following reads return
+// zeros, so the error can be checked once at the end.
+type Reader struct {
+	data    []byte
+	invalid error
+	err     error
+}
+
+// NewReader creates a reader of data that reports invalid when data is too short
+func NewReader(data []byte, invalid error) *Reader {
+	return &Reader{data: data, invalid: invalid}
+}
+
+// Err returns the error of the first failed read, or nil
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// Remaining returns the number of bytes not read yet
+func (r *Reader) Remaining() int {
+	return len(r.data)
+}
+
+// Fail marks the data as invalid, it is used when the values read are not valid
+func (r *Reader) Fail() {
+	if r.err == nil {
+		r.err = r.invalid
+	}
+}
+
+// Finish returns the error of the reads, or the invalid error if there is data left
+func (r *Reader) Finish() error {
+	if r.err == nil && len(r.data) > 0 {
+		r.Fail()
+	}
+	return r.err
+}
+
+func (r *Reader) take(size int) []byte {
+	if r.err != nil || len(r.data) < size {
+		r.Fail()
+		return make([]byte, size)
+	}
+	taken := r.data[:size]
+	r.data = r.data[size:]
+	return taken
+}
+
+// Byte reads a byte
+func (r *Reader) Byte() byte {
+	return r.take(1)[0]
+}
+
+// Bytes reads size bytes. The returned slice shares memory with the data.
+func (r *Reader) Bytes(size int) []byte {
+	if size < 0 || size > len(r.data) {
+		r.Fail()
+		return nil
+	}
+	return r.take(size)
+}
+
+// Uint16 reads 2 bytes number
+func (r *Reader) Uint16() uint16 {
+	return binary.LittleEndian.Uint16(r.take(2))
+}
+
+// Uint64 reads 8 bytes number
+func (r *Reader) Uint64() uint64 {
+	return binary.LittleEndian.Uint64(r.take(8))
+}
+
+// Uvarint reads a varint
+func (r *Reader) Uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	n, read := binary.Uvarint(r.data)
+	if read <= 0 {
+		r.Fail()
+		return 0
+	}
+	r.data = r.data[read:]
+	return n
+}
@@ -0,0 +1,40 @@
+package binenc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errInvalid = errors.New("invalid")
+
+func TestReader_reads_what_was_appended(t *testing.T) {
+	data := AppendUvarint(nil, 300)
+	data = AppendUint16(data, 0xABCD)
+	data = AppendUint64(data, 1<<60)
+	data = append(data, 7)
+
+	r := NewReader(data, errInvalid)
+
+	assert.Equal(t, uint64(300), r.Uvarint())
+	assert.Equal(t, uint16(0xABCD), r.Uint16())
+	assert.Equal(t, uint64(1<<60), r.Uint64())
+	assert.Equal(t, byte(7), r.Byte())
+	assert.Nil(t, r.Finish())
+}
+
+func TestReader_remembers_first_error(t *testing.T) {
+	r := NewReader([]byte{1, 2, 3}, errInvalid)
+
+	assert.Equal(t, uint64(0), r.Uint64())
+	assert.Equal(t, byte(0), r.Byte())
+	assert.Equal(t, errInvalid, r.Err())
+}
+
+func TestReader_Finish_fails_with_data_left(t *testing.T) {
+	r := NewReader([]byte{1, 2}, errInvalid)
+	r.Byte()
+
+	assert.Equal(t, errInvalid, r.Finish())
+}
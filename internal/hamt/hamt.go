@@ -0,0 +1,259 @@
+// Package hamt implements a persistent hash array mapped trie. Updates return a new map that shares most of its
+// structure with the previous one, which is never modified.
+package hamt
+
+import "math/bits"
+
+const (
+	bitsPerLevel = 5
+	levelMask    = 1<<bitsPerLevel - 1
+)
+
+// Map is a persistent map. The zero value is not usable, create it with New.
+type Map[K comparable, V any] struct {
+	root   *node[K, V]
+	size   int
+	hasher func(K) uint64
+}
+
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// leaf keeps all the entries whose keys have the same hash. There is more than one only on hash collisions.
+type leaf[K comparable, V any] struct {
+	hash    uint64
+	entries []entry[K, V]
+}
+
+// child is either a leaf or a node
+type child[K comparable, V any] struct {
+	leaf *leaf[K, V]
+	node *node[K, V]
+}
+
+// node has a child for each bit set in bitmap, in the order of the bits
+type node[K comparable, V any] struct {
+	bitmap   uint32
+	children []child[K, V]
+}
+
+// New creates an empty map that uses hasher for its keys
+func New[K comparable, V any](hasher func(K) uint64) Map[K, V] {
+	return Map[K, V]{root: &node[K, V]{}, hasher: hasher}
+}
+
+// IsZero returns true if the map was not created with New
+func (m Map[K, V]) IsZero() bool {
+	return m.root == nil
+}
+
+// Len returns the number of entries
+func (m Map[K, V]) Len() int {
+	return m.size
+}
+
+// Get returns the value of the key and true, or false if the key is not in the map
+func (m Map[K, V]) Get(key K) (V, bool) {
+	hash := m.hasher(key)
+	n := m.root
+
+	for shift := 0; ; shift += bitsPerLevel {
+		c, found := n.child(hash, shift)
+		if !found {
+			break
+		}
+		if c.node != nil {
+			n = c.node
+			continue
+		}
+		if c.leaf.hash == hash {
+			for _, e := range c.leaf.entries {
+				if e.key == key {
+					return e.value, true
+				}
+			}
+		}
+		break
+	}
+
+	var empty V
+	return empty, false
+}
+
+// Set returns a map with the key associated to value
+func (m Map[K, V]) Set(key K, value V) Map[K, V] {
+	root, added := m.root.set(m.hasher(key), 0, key, value)
+	if added {
+		m.size++
+	}
+	m.root = root
+	return m
+}
+
+// Delete returns a map without the key
+func (m Map[K, V]) Delete(key K) Map[K, V] {
+	root, removed := m.root.delete(m.hasher(key), 0, key)
+	if removed {
+		m.size--
+		m.root = root
+	}
+	return m
+}
+
+// Range calls fn with each entry, in no particular order. Iteration stops if fn returns false.
+func (m Map[K, V]) Range(fn func(K, V) bool) {
+	m.root.forEach(fn)
+}
+
+func index(hash uint64, shift int) uint32 {
+	return 1 << ((hash >> shift) & levelMask)
+}
+
+func (n *node[K, V]) position(bit uint32) int {
+	return bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+func (n *node[K, V]) child(hash uint64, shift int) (child[K, V], bool) {
+	bit := index(hash, shift)
+	if n.bitmap&bit == 0 {
+		return child[K, V]{}, false
+	}
+	return n.children[n.position(bit)], true
+}
+
+// with returns a copy of the node with the child at bit replaced or inserted
+func (n *node[K, V]) with(bit uint32, c child[K, V]) *node[K, V] {
+	pos := n.position(bit)
+
+	if n.bitmap&bit != 0 {
+		children := make([]child[K, V], len(n.children))
+		copy(children, n.children)
+		children[pos] = c
+		return &node[K, V]{n.bitmap, children}
+	}
+
+	children := make([]child[K, V], len(n.children)+1)
+	copy(children, n.children[:pos])
+	children[pos] = c
+	copy(children[pos+1:], n.children[pos:])
+	return &node[K, V]{n.bitmap | bit, children}
+}
+
+// without returns a copy of the node without the child at bit
+func (n *node[K, V]) without(bit uint32) *node[K, V] {
+	pos := n.position(bit)
+
+	children := make([]child[K, V], len(n.children)-1)
+	copy(children, n.children[:pos])
+	copy(children[pos:], n.children[pos+1:])
+	return &node[K, V]{n.bitmap &^ bit, children}
+}
+
+func (n *node[K, V]) set(hash uint64, shift int, key K, value V) (*node[K, V], bool) {
+	bit := index(hash, shift)
+	newLeaf := &leaf[K, V]{hash, []entry[K, V]{{key, value}}}
+
+	if n.bitmap&bit == 0 {
+		return n.with(bit, child[K, V]{leaf: newLeaf}), true
+	}
+
+	c := n.children[n.position(bit)]
+
+	if c.node != nil {
+		newNode, added := c.node.set(hash, shift+bitsPerLevel, key, value)
+		return n.with(bit, child[K, V]{node: newNode}), added
+	}
+
+	if c.leaf.hash != hash {
+		return n.with(bit, child[K, V]{node: merge(c.leaf, newLeaf, shift+bitsPerLevel)}), true
+	}
+
+	entries := make([]entry[K, V], len(c.leaf.entries), len(c.leaf.entries)+1)
+	copy(entries, c.leaf.entries)
+
+	for idx, e := range entries {
+		if e.key == key {
+			entries[idx].value = value
+			return n.with(bit, child[K, V]{leaf: &leaf[K, V]{hash, entries}}), false
+		}
+	}
+
+	entries = append(entries, entry[K, V]{key, value})
+	return n.with(bit, child[K, V]{leaf: &leaf[K, V]{hash, entries}}), true
+}
+
+// merge creates a node with two leaves with different hashes
+func merge[K comparable, V any](a, b *leaf[K, V], shift int) *node[K, V] {
+	bitA, bitB := index(a.hash, shift), index(b.hash, shift)
+
+	if bitA == bitB {
+		return &node[K, V]{bitA, []child[K, V]{{node: merge(a, b, shift+bitsPerLevel)}}}
+	}
+	if bitA > bitB {
+		a, b = b, a
+		bitA, bitB = bitB, bitA
+	}
+	return &node[K, V]{bitA | bitB, []child[K, V]{{leaf: a}, {leaf: b}}}
+}
+
+func (n *node[K, V]) delete(hash uint64, shift int, key K) (*node[K, V], bool) {
+	bit := index(hash, shift)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+
+	c := n.children[n.position(bit)]
+
+	if c.node != nil {
+		newNode, removed := c.node.delete(hash, shift+bitsPerLevel, key)
+		if !removed {
+			return n, false
+		}
+		switch {
+		case len(newNode.children) == 0:
+			return n.without(bit), true
+		case len(newNode.children) == 1 && newNode.children[0].leaf != nil:
+			// a single leaf does not need its own node
+			return n.with(bit, newNode.children[0]), true
+		default:
+			return n.with(bit, child[K, V]{node: newNode}), true
+		}
+	}
+
+	if c.leaf.hash != hash {
+		return n, false
+	}
+
+	for idx, e := range c.leaf.entries {
+		if e.key == key {
+			if len(c.leaf.entries) == 1 {
+				return n.without(bit), true
+			}
+			entries := make([]entry[K, V], 0, len(c.leaf.entries)-1)
+			entries = append(entries, c.leaf.entries[:idx]...)
+			entries = append(entries, c.leaf.entries[idx+1:]...)
+			return n.with(bit, child[K, V]{leaf: &leaf[K, V]{hash, entries}}), true
+		}
+	}
+
+	return n, false
+}
+
+func (n *node[K, V]) forEach(fn func(K, V) bool) bool {
+	for _, c := range n.children {
+		if c.node != nil {
+			if !c.node.forEach(fn) {
+				return false
+			}
+			continue
+		}
+		for _, e := range c.leaf.entries {
+			if !fn(e.key, e.value) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package hamt
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func identity(n int) uint64 { return uint64(n) }
+
+// colliding hashes many keys to the same value to exercise collision leaves
+func colliding(n int) uint64 { return uint64(n % 7) }
+
+func Test_Map_behaves_like_builtin_map(t *testing.T) {
+	for name, hasher := range map[string]func(int) uint64{"identity": identity, "colliding": colliding} {
+		t.Run(name, func(t *testing.T) {
+			random := rand.New(rand.NewSource(42))
+			expected := make(map[int]int)
+			m := New[int, int](hasher)
+
+			for step := 0; step < 5000; step++ {
+				key := random.Intn(500)
+				if random.Intn(3) == 0 {
+					delete(expected, key)
+					m = m.Delete(key)
+				} else {
+					expected[key] = step
+					m = m.Set(key, step)
+				}
+			}
+
+			assert.Equal(t, len(expected), m.Len())
+			for key, value := range expected {
+				got, found := m.Get(key)
+				assert.True(t, found)
+				assert.Equal(t, value, got)
+			}
+
+			seen := make(map[int]int)
+			m.Range(func(k, v int) bool {
+				seen[k] = v
+				return true
+			})
+			assert.Equal(t, expected, seen)
+		})
+	}
+}
+
+func Test_Map_updates_do_not_change_previous_versions(t *testing.T) {
+	v1 := New[int, string](identity).Set(1, "one").Set(33, "thirty-three")
+	v2 := v1.Set(1, "uno").Delete(33).Set(65, "sixty-five")
+
+	one, _ := v1.Get(1)
+	_, has33 := v1.Get(33)
+	_, has65 := v1.Get(65)
+	uno, _ := v2.Get(1)
+
+	assert.Equal(t, "one", one)
+	assert.True(t, has33)
+	assert.False(t, has65)
+	assert.Equal(t, "uno", uno)
+	assert.Equal(t, 2, v1.Len())
+	assert.Equal(t, 2, v2.Len())
+}
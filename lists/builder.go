@@ -0,0 +1,102 @@
+package lists
+
+// Builder builds a List adding elements in place, so building a list of n elements takes O(n) time instead of
+// the O(n^2) of calling Append n times. The zero value is an empty builder ready to use.
+//
+// Build returns a list that shares the elements of the builder without copying them. If the builder is changed
+// afterwards, it copies its elements first, so the lists already built never change.
+type Builder[T any] struct {
+	es []T
+	// shared is true when the elements were given to a list and must be copied before changing them
+	shared bool
+}
+
+// NewBuilder creates an empty builder with room for capacity elements
+func NewBuilder[T any](capacity int) *Builder[T] {
+	return &Builder[T]{es: make([]T, 0, capacity)}
+}
+
+// own copies the elements if they are shared with a built list
+func (b *Builder[T]) own() {
+	if b.shared {
+		es := make([]T, len(b.es), cap(b.es))
+		copy(es, b.es)
+		b.es = es
+		b.shared = false
+	}
+}
+
+// Add adds an element at the end
+func (b *Builder[T]) Add(t T) *Builder[T] {
+	b.own()
+	b.es = append(b.es, t)
+	return b
+}
+
+// AddAll adds all the elements at the end
+func (b *Builder[T]) AddAll(t ...T) *Builder[T] {
+	b.own()
+	b.es = append(b.es, t...)
+	return b
+}
+
+// Insert adds an element at idx, moving the following ones. It returns false if idx <0 or > Count.
+func (b *Builder[T]) Insert(idx int, t T) bool {
+	if idx < 0 || idx > len(b.es) {
+		return false
+	}
+	b.own()
+	var empty T
+	b.es = append(b.es, empty)
+	copy(b.es[idx+1:], b.es[idx:])
+	b.es[idx] = t
+	return true
+}
+
+// Remove removes and returns the element at idx, or reports empty element if idx <0 or >= Count
+func (b *Builder[T]) Remove(idx int) (T, bool) {
+	var empty T
+	if idx < 0 || idx >= len(b.es) {
+		return empty, false
+	}
+	b.own()
+	e := b.es[idx]
+	copy(b.es[idx:], b.es[idx+1:])
+	b.es[len(b.es)-1] = empty // do not retain a reference to the element
+	b.es = b.es[:len(b.es)-1]
+	return e, true
+}
+
+// Set replaces the element at idx. It returns false if idx <0 or >= Count.
+func (b *Builder[T]) Set(idx int, t T) bool {
+	if idx < 0 || idx >= len(b.es) {
+		return false
+	}
+	b.own()
+	b.es[idx] = t
+	return true
+}
+
+// At returns element at idx, or empty if idx <0 or >= Count
+func (b *Builder[T]) At(idx int) T {
+	if idx < 0 || idx >= len(b.es) {
+		var empty T
+		return empty
+	}
+	return b.es[idx]
+}
+
+// Count returns number of elements in the builder
+func (b *Builder[T]) Count() int {
+	return len(b.es)
+}
+
+// Build returns a list with the elements of the builder. The builder can still be used.
+func (b *Builder[T]) Build() List[T] {
+	if len(b.es) == 0 {
+		return Empty[T]()
+	}
+	b.shared = true
+	// the capacity is limited so appending to the list never writes into the builder memory
+	return &sliceList[T]{b.es[:len(b.es):len(b.es)]}
+}
@@ -0,0 +1,49 @@
+package lists
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder_builds_list(t *testing.T) {
+	b := NewBuilder[int](0)
+	for i := 0; i < 5; i++ {
+		b.Add(i)
+	}
+	b.AddAll(5, 6)
+	assert.True(t, b.Insert(0, -1))
+	assert.False(t, b.Insert(9, 100))
+	removed, _ := b.Remove(3)
+	b.Set(1, 10)
+
+	assert.Equal(t, 2, removed)
+	assert.Equal(t, Of(-1, 10, 1, 3, 4, 5, 6), b.Build())
+}
+
+func TestBuilder_changes_after_Build_do_not_modify_the_list(t *testing.T) {
+	var b Builder[string]
+	b.AddAll("a", "b", "c")
+
+	list := b.Build()
+	b.Set(0, "x")
+	b.Remove(1)
+	b.Add("d")
+
+	assert.Equal(t, []string{"a", "b", "c"}, list.Values())
+	assert.Equal(t, []string{"x", "c", "d"}, b.Build().Values())
+}
+
+func TestBuilder_Build_does_not_copy(t *testing.T) {
+	b := NewBuilder[int](3).AddAll(1, 2, 3)
+
+	list := b.Build().(*sliceList[int])
+
+	assert.Same(t, &b.es[0], &list.es[0])
+}
+
+func TestBuilder_empty_build_is_empty_list(t *testing.T) {
+	var b Builder[int]
+
+	assert.Equal(t, Empty[int](), b.Build())
+}
@@ -4,6 +4,6 @@ package lists
 Package lists provides an abstract List[T] type to operate with list.
 
 Different implementation can be provided, for example the slist package contains an implementation
-backed by a slice.
+backed by a slice, and LinkedList is a doubly linked list that can also be edited in place with a Cursor.
 
 */
@@ -0,0 +1,180 @@
+package lists
+
+import "github.com/totemcaf/gollections/slices"
+
+// fromSlice creates a slice backed list that owns es
+func fromSlice[T any](es []T) List[T] {
+	if len(es) == 0 {
+		return Empty[T]()
+	}
+	return &sliceList[T]{es}
+}
+
+// clamp limits n to the range 0 to count
+func clamp(n, count int) int {
+	if n < 0 {
+		return 0
+	}
+	if n > count {
+		return count
+	}
+	return n
+}
+
+// distinct returns the first occurrence of each element, using the same equality as Index
+func distinct[T any](es []T) []T {
+	var result []T
+	for _, e := range es {
+		found := false
+		for _, r := range result {
+			if areEqual(r, e) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+func reversed[T any](es []T) []T {
+	result := make([]T, len(es))
+	for idx, e := range es {
+		result[len(es)-1-idx] = e
+	}
+	return result
+}
+
+func (s *sliceList[T]) Prepend(t T) List[T] {
+	return &sliceList[T]{slices.InsertAt(s.es, 0, t)}
+}
+
+func (s *sliceList[T]) InsertAt(idx int, t T) (List[T], bool) {
+	if idx < 0 || idx > len(s.es) {
+		return nil, false
+	}
+	return &sliceList[T]{slices.InsertAt(s.es, idx, t)}, true
+}
+
+func (s *sliceList[T]) RemoveAt(idx int) (List[T], bool) {
+	if idx < 0 || idx >= len(s.es) {
+		return nil, false
+	}
+	return fromSlice(slices.RemoveAt(s.es, idx)), true
+}
+
+func (s *sliceList[T]) With(idx int, t T) (List[T], bool) {
+	if idx < 0 || idx >= len(s.es) {
+		return nil, false
+	}
+	result := s.Values()
+	result[idx] = t
+	return &sliceList[T]{result}, true
+}
+
+func (s *sliceList[T]) Slice(from, to int) (List[T], bool) {
+	if from < 0 || to > len(s.es) || from > to {
+		return nil, false
+	}
+	result := make([]T, to-from)
+	copy(result, s.es[from:to])
+	return fromSlice(result), true
+}
+
+func (s *sliceList[T]) Reverse() List[T] {
+	return fromSlice(reversed(s.es))
+}
+
+func (s *sliceList[T]) Take(n int) List[T] {
+	list, _ := s.Slice(0, clamp(n, len(s.es)))
+	return list
+}
+
+func (s *sliceList[T]) Drop(n int) List[T] {
+	list, _ := s.Slice(clamp(n, len(s.es)), len(s.es))
+	return list
+}
+
+func (s *sliceList[T]) Distinct() List[T] {
+	return fromSlice(distinct(s.es))
+}
+
+func (s *sliceList[T]) Remove(value T) List[T] {
+	idx := s.Index(value)
+	if idx < 0 {
+		return s
+	}
+	list, _ := s.RemoveAt(idx)
+	return list
+}
+
+func (l *LinkedList[T]) Prepend(t T) List[T] {
+	result := NewLinkedList(l.Values()...)
+	result.PushFront(t)
+	return result
+}
+
+func (l *LinkedList[T]) InsertAt(idx int, t T) (List[T], bool) {
+	if idx < 0 || idx > l.count {
+		return nil, false
+	}
+	return NewLinkedList(slices.InsertAt(l.Values(), idx, t)...), true
+}
+
+func (l *LinkedList[T]) RemoveAt(idx int) (List[T], bool) {
+	if idx < 0 || idx >= l.count {
+		return nil, false
+	}
+	return NewLinkedList(slices.RemoveAt(l.Values(), idx)...), true
+}
+
+func (l *LinkedList[T]) With(idx int, t T) (List[T], bool) {
+	if idx < 0 || idx >= l.count {
+		return nil, false
+	}
+	result := l.Values()
+	result[idx] = t
+	return NewLinkedList(result...), true
+}
+
+func (l *LinkedList[T]) Slice(from, to int) (List[T], bool) {
+	if from < 0 || to > l.count || from > to {
+		return nil, false
+	}
+	return NewLinkedList(l.Values()[from:to]...), true
+}
+
+func (l *LinkedList[T]) Reverse() List[T] {
+	result := NewLinkedList[T]()
+	l.forEach(func(_ int, e T) bool {
+		result.PushFront(e)
+		return true
+	})
+	return result
+}
+
+func (l *LinkedList[T]) Take(n int) List[T] {
+	list, _ := l.Slice(0, clamp(n, l.count))
+	return list
+}
+
+func (l *LinkedList[T]) Drop(n int) List[T] {
+	list, _ := l.Slice(clamp(n, l.count), l.count)
+	return list
+}
+
+func (l *LinkedList[T]) Distinct() List[T] {
+	return NewLinkedList(distinct(l.Values())...)
+}
+
+func (l *LinkedList[T]) Remove(value T) List[T] {
+	idx := l.Index(value)
+	if idx < 0 {
+		// the linked list can change in place, so the result must not be the receiver
+		return NewLinkedList(l.Values()...)
+	}
+	list, _ := l.RemoveAt(idx)
+	return list
+}
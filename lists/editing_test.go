@@ -0,0 +1,86 @@
+package lists
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var implementations = map[string]func(...string) List[string]{
+	"slice":  Of[string],
+	"linked": LinkedOf[string],
+}
+
+func TestList_editing_operations(t *testing.T) {
+	for name, of := range implementations {
+		t.Run(name, func(t *testing.T) {
+			list := of("a", "b", "c", "b")
+
+			inserted, ok := list.InsertAt(4, "z")
+			assert.True(t, ok)
+			assert.Equal(t, []string{"a", "b", "c", "b", "z"}, inserted.Values())
+
+			removed, ok := list.RemoveAt(0)
+			assert.True(t, ok)
+			assert.Equal(t, []string{"b", "c", "b"}, removed.Values())
+
+			replaced, ok := list.With(2, "C")
+			assert.True(t, ok)
+			assert.Equal(t, []string{"a", "b", "C", "b"}, replaced.Values())
+
+			sliced, ok := list.Slice(1, 3)
+			assert.True(t, ok)
+			assert.Equal(t, []string{"b", "c"}, sliced.Values())
+
+			assert.Equal(t, []string{"z", "a", "b", "c", "b"}, list.Prepend("z").Values())
+			assert.Equal(t, []string{"b", "c", "b", "a"}, list.Reverse().Values())
+			assert.Equal(t, []string{"a", "b"}, list.Take(2).Values())
+			assert.Equal(t, []string{"c", "b"}, list.Drop(2).Values())
+			assert.Equal(t, []string{"a", "b", "c"}, list.Distinct().Values())
+			assert.Equal(t, []string{"a", "c", "b"}, list.Remove("b").Values())
+			assert.Equal(t, list.Values(), list.Remove("x").Values())
+
+			// the original list does not change
+			assert.Equal(t, []string{"a", "b", "c", "b"}, list.Values())
+		})
+	}
+}
+
+func TestList_editing_out_of_bounds(t *testing.T) {
+	for name, of := range implementations {
+		t.Run(name, func(t *testing.T) {
+			list := of("a", "b")
+
+			_, insertOk := list.InsertAt(3, "x")
+			_, removeOk := list.RemoveAt(2)
+			_, withOk := list.With(-1, "x")
+			_, sliceOk := list.Slice(1, 0)
+			_, sliceEndOk := list.Slice(0, 3)
+
+			assert.False(t, insertOk)
+			assert.False(t, removeOk)
+			assert.False(t, withOk)
+			assert.False(t, sliceOk)
+			assert.False(t, sliceEndOk)
+			assert.Equal(t, []string{"a", "b"}, list.Take(10).Values())
+			assert.Equal(t, 0, list.Drop(10).Count())
+			assert.Equal(t, 0, list.Take(-1).Count())
+		})
+	}
+}
+
+func TestLinkedList_Remove_missing_value_returns_a_copy(t *testing.T) {
+	l := NewLinkedList("a", "b")
+
+	removed := l.Remove("x")
+	l.PushBack("c")
+
+	assert.Equal(t, []string{"a", "b"}, removed.Values())
+}
+
+func TestSliceList_editing_to_empty_is_Empty(t *testing.T) {
+	removed, _ := Of("a").RemoveAt(0)
+
+	assert.Equal(t, Empty[string](), removed)
+	assert.Equal(t, Empty[string](), Of("a").Drop(1))
+}
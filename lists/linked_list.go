@@ -0,0 +1,408 @@
+package lists
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/totemcaf/gollections/types"
+)
+
+type node[T any] struct {
+	value T
+	// list is the list that holds the node, or nil once the node is removed
+	list       *LinkedList[T]
+	prev, next *node[T]
+}
+
+// noCopy makes go vet report copies of the struct that contains it
+type noCopy struct{}
+
+func (*noCopy) Lock()   {}
+func (*noCopy) Unlock() {}
+
+// LinkedList is a doubly linked list. As a List its methods return new lists and do not modify it, but it can also
+// be modified in place from both ends and, with a Cursor, in the middle, all in constant time.
+// The zero value is an empty list ready to use. A LinkedList must not be copied by value once used, because its
+// elements point to the sentinel of the original list; use it through a pointer.
+type LinkedList[T any] struct {
+	noCopy noCopy
+	// root is a sentinel: root.next is the first element and root.prev the last one
+	root  node[T]
+	count int
+}
+
+// NewLinkedList creates a linked list with the given elements
+func NewLinkedList[T any](e ...T) *LinkedList[T] {
+	l := &LinkedList[T]{}
+	for _, v := range e {
+		l.PushBack(v)
+	}
+	return l
+}
+
+// LinkedOf creates a linked list with the given elements as a List
+func LinkedOf[T any](e ...T) List[T] {
+	return NewLinkedList(e...)
+}
+
+func (l *LinkedList[T]) lazyInit() {
+	if l.root.next == nil {
+		l.root.next = &l.root
+		l.root.prev = &l.root
+	}
+}
+
+func (l *LinkedList[T]) first() *node[T] {
+	if l.count == 0 {
+		return nil
+	}
+	return l.root.next
+}
+
+func (l *LinkedList[T]) insertAfter(at *node[T], value T) *node[T] {
+	n := &node[T]{value: value, list: l, prev: at, next: at.next}
+	at.next.prev = n
+	at.next = n
+	l.count++
+	return n
+}
+
+func (l *LinkedList[T]) unlink(n *node[T]) {
+	n.prev.next = n.next
+	n.next.prev = n.prev
+	n.prev, n.next, n.list = nil, nil, nil
+	l.count--
+}
+
+// forEach calls fn with each element until it returns false
+func (l *LinkedList[T]) forEach(fn func(idx int, e T) bool) {
+	idx := 0
+	for n := l.first(); n != nil && n != &l.root; n = n.next {
+		if !fn(idx, n.value) {
+			return
+		}
+		idx++
+	}
+}
+
+// PushBack adds an element at the end of this list
+func (l *LinkedList[T]) PushBack(value T) {
+	l.lazyInit()
+	l.insertAfter(l.root.prev, value)
+}
+
+// PushFront adds an element at the start of this list
+func (l *LinkedList[T]) PushFront(value T) {
+	l.lazyInit()
+	l.insertAfter(&l.root, value)
+}
+
+// PopFront removes and returns the first element of this list, or reports the list is empty
+func (l *LinkedList[T]) PopFront() (T, bool) {
+	return l.Front().Remove()
+}
+
+// PopBack removes and returns the last element of this list, or reports the list is empty
+func (l *LinkedList[T]) PopBack() (T, bool) {
+	return l.Back().Remove()
+}
+
+// Front returns a cursor at the first element. If the list is empty, the cursor is not valid.
+func (l *LinkedList[T]) Front() *Cursor[T] {
+	return &Cursor[T]{l, l.first()}
+}
+
+// Back returns a cursor at the last element. If the list is empty, the cursor is not valid.
+func (l *LinkedList[T]) Back() *Cursor[T] {
+	if l.count == 0 {
+		return &Cursor[T]{l, nil}
+	}
+	return &Cursor[T]{l, l.root.prev}
+}
+
+func (l *LinkedList[T]) Values() []T {
+	values := make([]T, 0, l.count)
+	l.forEach(func(_ int, e T) bool {
+		values = append(values, e)
+		return true
+	})
+	return values
+}
+
+func (l *LinkedList[T]) Append(t T) List[T] {
+	return l.AppendAll(t)
+}
+
+func (l *LinkedList[T]) AppendAll(t ...T) List[T] {
+	return NewLinkedList(append(l.Values(), t...)...)
+}
+
+func (l *LinkedList[T]) Concat(second List[T]) List[T] {
+	return l.AppendAll(second.Values()...)
+}
+
+func (l *LinkedList[T]) Count() int {
+	return l.count
+}
+
+func (l *LinkedList[T]) CountBy(predicate types.Predicate[T]) int {
+	count := 0
+	l.forEach(func(_ int, e T) bool {
+		if predicate(e) {
+			count++
+		}
+		return true
+	})
+	return count
+}
+
+// At2 returns element at idx, or report empty element if idx <0 or >= List.Count. It walks the list from the
+// nearest end.
+func (l *LinkedList[T]) At2(idx int) (T, bool) {
+	if idx < 0 || idx >= l.count {
+		var empty T
+		return empty, false
+	}
+
+	if idx < l.count/2 {
+		n := l.root.next
+		for ; idx > 0; idx-- {
+			n = n.next
+		}
+		return n.value, true
+	}
+
+	n := l.root.prev
+	for idx = l.count - 1 - idx; idx > 0; idx-- {
+		n = n.prev
+	}
+	return n.value, true
+}
+
+func (l *LinkedList[T]) At(idx int) T {
+	e, _ := l.At2(idx)
+	return e
+}
+
+func (l *LinkedList[T]) Map(mapper func(T) T) List[T] {
+	result := NewLinkedList[T]()
+	l.forEach(func(_ int, e T) bool {
+		result.PushBack(mapper(e))
+		return true
+	})
+	return result
+}
+
+// Reduce convert this list in a single value of the same type
+func (l *LinkedList[T]) Reduce(reducer func(accum T, element T) T) T {
+	var result T
+	return l.Fold(result, reducer)
+}
+
+// Fold convert this list in a single value of the same type
+func (l *LinkedList[T]) Fold(initial T, reducer func(accum T, element T) T) T {
+	result := initial
+	l.forEach(func(_ int, e T) bool {
+		result = reducer(result, e)
+		return true
+	})
+	return result
+}
+
+func (l *LinkedList[T]) FilterBy(predicate types.Predicate[T]) List[T] {
+	result := NewLinkedList[T]()
+	l.forEach(func(_ int, e T) bool {
+		if predicate(e) {
+			result.PushBack(e)
+		}
+		return true
+	})
+	return result
+}
+
+func (l *LinkedList[T]) Any(predicate types.Predicate[T]) bool {
+	return l.IndexBy(predicate) >= 0
+}
+
+func (l *LinkedList[T]) All(predicate types.Predicate[T]) bool {
+	return l.IndexBy(func(e T) bool { return !predicate(e) }) < 0
+}
+
+func (l *LinkedList[T]) Index(t T) int {
+	return l.IndexBy(func(e T) bool { return areEqual(e, t) })
+}
+
+func (l *LinkedList[T]) Index2(t T) (int, bool) {
+	idx := l.Index(t)
+	return idx, idx >= 0
+}
+
+func (l *LinkedList[T]) IndexBy(predicate types.Predicate[T]) int {
+	found := -1
+	l.forEach(func(idx int, e T) bool {
+		if predicate(e) {
+			found = idx
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+func (l *LinkedList[T]) IndexBy2(predicate types.Predicate[T]) (int, bool) {
+	idx := l.IndexBy(predicate)
+	return idx, idx >= 0
+}
+
+func (l *LinkedList[T]) Join(separator string) string {
+	var builder strings.Builder
+	l.forEach(func(idx int, e T) bool {
+		if idx > 0 {
+			builder.WriteString(separator)
+		}
+		builder.WriteString(toString(e))
+		return true
+	})
+	return builder.String()
+}
+
+// String returns a string representation of the list
+func (l *LinkedList[T]) String() string {
+	return "[" + l.Join(" ") + "]"
+}
+
+func (l *LinkedList[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.Values())
+}
+
+func (l *LinkedList[T]) UnmarshalJSON(data []byte) error {
+	var elements []T
+
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return err
+	}
+
+	// detach the current elements so the cursors on them are no longer valid
+	for n := l.first(); n != nil && n != &l.root; n = n.next {
+		n.list = nil
+	}
+
+	*l = LinkedList[T]{}
+	for _, e := range elements {
+		l.PushBack(e)
+	}
+
+	return nil
+}
+
+// Cursor points to an element of a LinkedList to read, change, insert or remove elements around it in constant
+// time. A cursor is not valid when it moves past the ends of the list or when the list is empty.
+// A cursor is also not valid when its element is removed by other means, such as PopFront, PopBack or another
+// cursor. Then all its methods do nothing.
+type Cursor[T any] struct {
+	list *LinkedList[T]
+	node *node[T]
+}
+
+// at returns the node of the cursor, or nil if the cursor is past the ends or its element was removed
+func (c *Cursor[T]) at() *node[T] {
+	if c.node == nil || c.node.list != c.list {
+		return nil
+	}
+	return c.node
+}
+
+// removed returns true if the element of the cursor was removed by other means
+func (c *Cursor[T]) removed() bool {
+	return c.node != nil && c.node.list != c.list
+}
+
+// Valid returns true if the cursor points to an element
+func (c *Cursor[T]) Valid() bool {
+	return c.at() != nil
+}
+
+// Value returns the element at the cursor, or the zero value if the cursor is not valid
+func (c *Cursor[T]) Value() T {
+	n := c.at()
+	if n == nil {
+		var empty T
+		return empty
+	}
+	return n.value
+}
+
+// Set replaces the element at the cursor. It returns false if the cursor is not valid.
+func (c *Cursor[T]) Set(value T) bool {
+	n := c.at()
+	if n == nil {
+		return false
+	}
+	n.value = value
+	return true
+}
+
+// Next moves the cursor to the next element and returns true if there is one
+func (c *Cursor[T]) Next() bool {
+	if c.at() == nil {
+		return false
+	}
+	c.node = c.node.next
+	if c.node == &c.list.root {
+		c.node = nil
+	}
+	return c.node != nil
+}
+
+// Prev moves the cursor to the previous element and returns true if there is one
+func (c *Cursor[T]) Prev() bool {
+	if c.at() == nil {
+		return false
+	}
+	c.node = c.node.prev
+	if c.node == &c.list.root {
+		c.node = nil
+	}
+	return c.node != nil
+}
+
+// InsertBefore adds an element before the one at the cursor, the cursor does not move. If the cursor is past the
+// ends of the list the element is added at the end of the list.
+func (c *Cursor[T]) InsertBefore(value T) {
+	switch {
+	case c.removed():
+		return
+	case c.node == nil:
+		c.list.PushBack(value)
+	default:
+		c.list.insertAfter(c.node.prev, value)
+	}
+}
+
+// InsertAfter adds an element after the one at the cursor, the cursor does not move. If the cursor is past the
+// ends of the list the element is added at the start of the list.
+func (c *Cursor[T]) InsertAfter(value T) {
+	switch {
+	case c.removed():
+		return
+	case c.node == nil:
+		c.list.PushFront(value)
+	default:
+		c.list.insertAfter(c.node, value)
+	}
+}
+
+// Remove removes the element at the cursor and returns it, moving the cursor to the next element. It reports false
+// if the cursor is not valid.
+func (c *Cursor[T]) Remove() (T, bool) {
+	removed := c.at()
+	if removed == nil {
+		var empty T
+		return empty, false
+	}
+
+	c.Next()
+	c.list.unlink(removed)
+
+	return removed.value, true
+}
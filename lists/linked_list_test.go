@@ -0,0 +1,128 @@
+package lists
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLinkedList_behaves_as_List(t *testing.T) {
+	var list List[string] = LinkedOf("one", "ring", "to", "rule")
+
+	appended := list.Append("them")
+	isLong := func(s string) bool { return len(s) > 3 }
+
+	assert.Equal(t, 4, list.Count())
+	assert.Equal(t, []string{"one", "ring", "to", "rule", "them"}, appended.Values())
+	assert.Equal(t, "ring", list.At(1))
+	assert.Equal(t, "rule", list.At(3))
+	_, found := list.At2(4)
+	assert.False(t, found)
+	assert.Equal(t, []string{"ring", "rule"}, list.FilterBy(isLong).Values())
+	assert.Equal(t, 2, list.CountBy(isLong))
+	assert.Equal(t, "ONE-RING-TO-RULE", list.Map(strings.ToUpper).Join("-"))
+	assert.Equal(t, 2, list.Index("to"))
+	assert.Equal(t, -1, list.Index("them"))
+	assert.True(t, list.Any(isLong))
+	assert.False(t, list.All(isLong))
+	assert.Equal(t, "onering", list.Fold("", func(a, e string) string {
+		if a == "" || len(a) < 4 {
+			return a + e
+		}
+		return a
+	}))
+}
+
+func TestLinkedList_AppendAll_without_elements_returns_a_copy(t *testing.T) {
+	l := NewLinkedList(1, 2)
+
+	appended := l.AppendAll()
+	l.PushBack(3)
+
+	assert.Equal(t, []int{1, 2}, appended.Values())
+}
+
+func TestLinkedList_push_and_pop(t *testing.T) {
+	var l LinkedList[int]
+	l.PushBack(2)
+	l.PushFront(1)
+	l.PushBack(3)
+
+	first, _ := l.PopFront()
+	last, _ := l.PopBack()
+
+	assert.Equal(t, 1, first)
+	assert.Equal(t, 3, last)
+	assert.Equal(t, []int{2}, l.Values())
+
+	l.PopFront()
+	_, found := l.PopFront()
+	assert.False(t, found)
+}
+
+func TestCursor_edits_in_the_middle(t *testing.T) {
+	l := NewLinkedList(1, 2, 3, 4, 5)
+
+	// remove even numbers and add a copy of each odd one after it
+	for c := l.Front(); c.Valid(); {
+		v := c.Value()
+		if v%2 == 0 {
+			c.Remove()
+			continue
+		}
+		c.InsertAfter(v * 10)
+		c.Next()
+		c.Next()
+	}
+
+	assert.Equal(t, []int{1, 10, 3, 30, 5, 50}, l.Values())
+
+	c := l.Back()
+	c.Prev()
+	c.Set(500)
+	c.InsertBefore(4)
+	assert.Equal(t, "[1 10 3 30 4 500 50]", l.String())
+
+	assert.False(t, c.Next() && c.Next())
+	assert.False(t, c.Valid())
+}
+
+func TestCursor_is_not_valid_after_its_element_is_removed_elsewhere(t *testing.T) {
+	l := NewLinkedList(1, 2, 3)
+	first, second, last := l.Front(), l.Front(), l.Back()
+	second.Next()
+
+	l.PopFront()
+	l.PopBack()
+	_, removed := l.Front().Remove()
+
+	assert.True(t, removed)
+	for _, c := range []*Cursor[int]{first, second, last} {
+		assert.False(t, c.Valid())
+		assert.Equal(t, 0, c.Value())
+		assert.False(t, c.Set(10))
+		assert.False(t, c.Next())
+		assert.False(t, c.Prev())
+		c.InsertBefore(20)
+		c.InsertAfter(30)
+		_, ok := c.Remove()
+		assert.False(t, ok)
+	}
+	assert.Equal(t, 0, l.Count())
+	assert.Empty(t, l.Values())
+}
+
+func TestLinkedList_json(t *testing.T) {
+	empty, err := json.Marshal(NewLinkedList[string]())
+	assert.Nil(t, err)
+	assert.Equal(t, `[]`, string(empty))
+
+	data, _ := json.Marshal(LinkedOf(&sample{"Elton", 42}))
+	assert.Equal(t, `[{"Name":"Elton","Age":42}]`, string(data))
+
+	var l LinkedList[int]
+	assert.Nil(t, json.Unmarshal([]byte(`[3,4]`), &l))
+	assert.Equal(t, []int{3, 4}, l.Values())
+}
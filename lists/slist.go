@@ -135,7 +135,7 @@ func (s *sliceList[T]) Any(predicate types.Predicate[T]) bool {
 	return false
 }
 
-func (s *sliceList[T]) areEqual(a interface{}, b T) bool {
+func areEqual[T any](a interface{}, b T) bool {
 	if ac, ok := a.(types.Comparable[T]); ok {
 		return ac.Compare(b) == 0
 	}
@@ -153,7 +153,7 @@ func (s *sliceList[T]) All(predicate types.Predicate[T]) bool {
 
 func (s *sliceList[T]) Index(t T) int {
 	for idx, e := range s.es {
-		if s.areEqual(e, t) {
+		if areEqual(e, t) {
 			return idx
 		}
 	}
@@ -179,10 +179,10 @@ func (s *sliceList[T]) IndexBy2(t types.Predicate[T]) (int, bool) {
 	return idx, idx >= 0
 }
 
-func (s *sliceList[T]) toString(x T) string {
+func toString[T any](x T) string {
 	return fmt.Sprintf("%v", x)
 }
 
 func (s *sliceList[T]) Join(separator string) string {
-	return strings.Join(slices.Map(s.es, s.toString), separator)
+	return strings.Join(slices.Map(s.es, toString[T]), separator)
 }
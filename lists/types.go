@@ -47,4 +47,26 @@ type List[T any] interface {
 	IndexBy2(types.Predicate[T]) (int, bool)
 	// Join makes all elements a single string separting values by separator
 	Join(separator string) string
+
+	// Prepend returns a list with a new element followed by all the elements of this
+	Prepend(T) List[T]
+	// InsertAt returns a list with a new element at idx, or reports false if idx <0 or > List.Count
+	InsertAt(idx int, t T) (List[T], bool)
+	// RemoveAt returns a list without the element at idx, or reports false if idx <0 or >= List.Count
+	RemoveAt(idx int) (List[T], bool)
+	// With returns a list with the element at idx replaced, or reports false if idx <0 or >= List.Count
+	With(idx int, t T) (List[T], bool)
+	// Slice returns a list with the elements from index from to index to, excluding to, or reports false if
+	// from <0, to > List.Count or from > to
+	Slice(from, to int) (List[T], bool)
+	// Reverse returns a list with the elements of this in reverse order
+	Reverse() List[T]
+	// Take returns a list with the first n elements, or all of them if there are fewer
+	Take(n int) List[T]
+	// Drop returns a list without the first n elements, or an empty list if there are fewer
+	Drop(n int) List[T]
+	// Distinct returns a list with the first occurrence of each element, using the same equality as Index
+	Distinct() List[T]
+	// Remove returns a list without the first occurrence of value, or this list if value is not in it
+	Remove(value T) List[T]
 }
@@ -0,0 +1,110 @@
+package maps
+
+import (
+	"github.com/totemcaf/gollections/hashes"
+	"github.com/totemcaf/gollections/internal/hamt"
+)
+
+// Persistent is an immutable map. Operations that change it return a new map, leaving the original untouched.
+// New versions share most of their structure, so updates are cheap. The zero value is an empty map ready to use.
+type Persistent[K comparable, V any] struct {
+	tree hamt.Map[K, V]
+}
+
+// NewPersistent creates an empty persistent map
+func NewPersistent[K comparable, V any]() Persistent[K, V] {
+	return Persistent[K, V]{hamt.New[K, V](hashes.For[K]())}
+}
+
+// PersistentOf creates a persistent map with the entries of a map
+func PersistentOf[K comparable, V any](m map[K]V) Persistent[K, V] {
+	p := NewPersistent[K, V]()
+	for k, v := range m {
+		p.tree = p.tree.Set(k, v)
+	}
+	return p
+}
+
+// Get returns the value of the key and true, or the zero value and false if the key is not in the map
+func (p Persistent[K, V]) Get(key K) (V, bool) {
+	if p.tree.IsZero() {
+		var empty V
+		return empty, false
+	}
+	return p.tree.Get(key)
+}
+
+// Contains returns true if the key is in the map
+func (p Persistent[K, V]) Contains(key K) bool {
+	_, found := p.Get(key)
+	return found
+}
+
+// Len returns the number of entries in the map
+func (p Persistent[K, V]) Len() int {
+	return p.tree.Len()
+}
+
+// With returns a map with the entries of this map and key associated to value
+func (p Persistent[K, V]) With(key K, value V) Persistent[K, V] {
+	if p.tree.IsZero() {
+		p = NewPersistent[K, V]()
+	}
+	return Persistent[K, V]{p.tree.Set(key, value)}
+}
+
+// Without returns a map with the entries of this map except the one of key
+func (p Persistent[K, V]) Without(key K) Persistent[K, V] {
+	if p.tree.IsZero() {
+		return p
+	}
+	return Persistent[K, V]{p.tree.Delete(key)}
+}
+
+// Range calls fn with each entry of the map, in no particular order. Iteration stops if fn returns false.
+func (p Persistent[K, V]) Range(fn func(K, V) bool) {
+	if p.tree.IsZero() {
+		return
+	}
+	p.tree.Range(fn)
+}
+
+// Keys returns the keys of the map
+func (p Persistent[K, V]) Keys() []K {
+	keys := make([]K, 0, p.Len())
+	p.Range(func(k K, _ V) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return keys
+}
+
+// Values returns the values of the map
+func (p Persistent[K, V]) Values() []V {
+	values := make([]V, 0, p.Len())
+	p.Range(func(_ K, v V) bool {
+		values = append(values, v)
+		return true
+	})
+	return values
+}
+
+// Entries returns the entries of the map
+func (p Persistent[K, V]) Entries() []Entry[K, V] {
+	entries := make([]Entry[K, V], 0, p.Len())
+	p.Range(func(k K, v V) bool {
+		entries = append(entries, Entry[K, V]{k, v})
+		return true
+	})
+	return entries
+}
+
+// ToMap returns a new mutable map with the entries of the map
+func (p Persistent[K, V]) ToMap() map[K]V {
+	m := make(map[K]V, p.Len())
+	p.Range(func(k K, v V) bool {
+		m[k] = v
+		return true
+	})
+	return m
+}
@@ -0,0 +1,39 @@
+package maps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPersistent_updates_return_new_maps(t *testing.T) {
+	original := PersistentOf(map[string]int{"a": 1, "b": 2})
+
+	changed := original.With("a", 10).With("c", 3)
+	removed := original.Without("b")
+
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, original.ToMap())
+	assert.Equal(t, map[string]int{"a": 10, "b": 2, "c": 3}, changed.ToMap())
+	assert.Equal(t, map[string]int{"a": 1}, removed.ToMap())
+}
+
+func TestPersistent_zero_value_is_empty_map(t *testing.T) {
+	var empty Persistent[string, int]
+
+	_, found := empty.Get("a")
+
+	assert.False(t, found)
+	assert.Equal(t, 0, empty.Len())
+	assert.Equal(t, 1, empty.With("a", 1).Len())
+	assert.Empty(t, empty.Without("a").Keys())
+}
+
+func TestPersistent_struct_keys(t *testing.T) {
+	type point struct{ X, Y int }
+
+	m := NewPersistent[point, string]().With(point{1, 2}, "a").With(point{2, 1}, "b")
+
+	value, _ := m.Get(point{1, 2})
+	assert.Equal(t, "a", value)
+	assert.ElementsMatch(t, []string{"a", "b"}, m.Values())
+}
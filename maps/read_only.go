@@ -0,0 +1,58 @@
+package maps
+
+// ReadOnly is a view of a map that only exposes queries. It can be given to code that must not modify the map.
+// The view is not a copy: changes made through the original map are visible.
+type ReadOnly[K comparable, V any] struct {
+	m map[K]V
+}
+
+// AsReadOnly returns a read-only view of the map
+func AsReadOnly[K comparable, V any](m map[K]V) ReadOnly[K, V] {
+	return ReadOnly[K, V]{m}
+}
+
+// Get returns the value of the key and true, or the zero value and false if the key is not in the map
+func (r ReadOnly[K, V]) Get(key K) (V, bool) {
+	v, found := r.m[key]
+	return v, found
+}
+
+// Contains returns true if the key is in the map
+func (r ReadOnly[K, V]) Contains(key K) bool {
+	_, found := r.m[key]
+	return found
+}
+
+// Len returns the number of entries in the map
+func (r ReadOnly[K, V]) Len() int {
+	return len(r.m)
+}
+
+// Keys returns the keys of the map
+func (r ReadOnly[K, V]) Keys() []K {
+	return Keys(r.m)
+}
+
+// Values returns the values of the map
+func (r ReadOnly[K, V]) Values() []V {
+	return Values(r.m)
+}
+
+// Entries returns the entries of the map
+func (r ReadOnly[K, V]) Entries() []Entry[K, V] {
+	return Entries(r.m)
+}
+
+// Range calls fn with each entry of the map. Iteration stops if fn returns false.
+func (r ReadOnly[K, V]) Range(fn func(K, V) bool) {
+	for k, v := range r.m {
+		if !fn(k, v) {
+			return
+		}
+	}
+}
+
+// Clone returns a new mutable map with the entries of the map
+func (r ReadOnly[K, V]) Clone() map[K]V {
+	return Clone(r.m)
+}
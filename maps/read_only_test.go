@@ -0,0 +1,29 @@
+package maps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOnly_sees_changes_of_the_map(t *testing.T) {
+	m := map[string]int{"a": 1}
+	view := AsReadOnly(m)
+
+	m["b"] = 2
+
+	value, found := view.Get("b")
+	assert.True(t, found)
+	assert.Equal(t, 2, value)
+	assert.Equal(t, 2, view.Len())
+	assert.False(t, view.Contains("c"))
+}
+
+func TestReadOnly_Clone_is_independent(t *testing.T) {
+	m := map[string]int{"a": 1}
+
+	cloned := AsReadOnly(m).Clone()
+	cloned["a"] = 100
+
+	assert.Equal(t, 1, m["a"])
+}
@@ -0,0 +1,217 @@
+// Package queues provides LIFO, FIFO and priority containers: a double ended queue, the Stack and Queue built on
+// it, and a PriorityQueue.
+package queues
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const minCapacity = 8
+
+// Deque is a double ended queue backed by a ring buffer. Adding and removing elements at both ends takes constant
+// time, and elements can be accessed by index from the front. The zero value is an empty deque ready to use.
+type Deque[T any] struct {
+	buffer []T
+	head   int
+	size   int
+}
+
+// NewDeque creates an empty deque with room for capacity elements before it needs to grow
+func NewDeque[T any](capacity int) *Deque[T] {
+	if capacity < minCapacity {
+		capacity = minCapacity
+	}
+	return &Deque[T]{buffer: make([]T, capacity)}
+}
+
+// DequeOf creates a deque with the given elements, the first one at the front
+func DequeOf[T any](es ...T) *Deque[T] {
+	d := NewDeque[T](len(es))
+	for _, e := range es {
+		d.PushBack(e)
+	}
+	return d
+}
+
+// position returns the position in the buffer of the element at idx from the front
+func (d *Deque[T]) position(idx int) int {
+	return (d.head + idx) % len(d.buffer)
+}
+
+// resize moves the elements to a new buffer of the given capacity, starting at position 0
+func (d *Deque[T]) resize(capacity int) {
+	buffer := make([]T, capacity)
+	if d.head+d.size <= len(d.buffer) {
+		copy(buffer, d.buffer[d.head:d.head+d.size])
+	} else {
+		n := copy(buffer, d.buffer[d.head:])
+		copy(buffer[n:], d.buffer[:d.size-n])
+	}
+	d.buffer = buffer
+	d.head = 0
+}
+
+func (d *Deque[T]) grow() {
+	if d.size < len(d.buffer) {
+		return
+	}
+	capacity := 2 * len(d.buffer)
+	if capacity < minCapacity {
+		capacity = minCapacity
+	}
+	d.resize(capacity)
+}
+
+// shrink releases memory when the deque uses less than a quarter of its buffer
+func (d *Deque[T]) shrink() {
+	if len(d.buffer) > minCapacity && d.size <= len(d.buffer)/4 {
+		d.resize(len(d.buffer) / 2)
+	}
+}
+
+// PushBack adds an element at the back
+func (d *Deque[T]) PushBack(e T) {
+	d.grow()
+	d.buffer[d.position(d.size)] = e
+	d.size++
+}
+
+// PushFront adds an element at the front
+func (d *Deque[T]) PushFront(e T) {
+	d.grow()
+	d.head = (d.head - 1 + len(d.buffer)) % len(d.buffer)
+	d.buffer[d.head] = e
+	d.size++
+}
+
+// PopFront removes and returns the element at the front, or reports the deque is empty
+func (d *Deque[T]) PopFront() (T, bool) {
+	var empty T
+	if d.size == 0 {
+		return empty, false
+	}
+	e := d.buffer[d.head]
+	d.buffer[d.head] = empty // do not retain a reference to the element
+	d.head = d.position(1)
+	d.size--
+	d.shrink()
+	return e, true
+}
+
+// PopBack removes and returns the element at the back, or reports the deque is empty
+func (d *Deque[T]) PopBack() (T, bool) {
+	var empty T
+	if d.size == 0 {
+		return empty, false
+	}
+	last := d.position(d.size - 1)
+	e := d.buffer[last]
+	d.buffer[last] = empty
+	d.size--
+	d.shrink()
+	return e, true
+}
+
+// Front returns the element at the front without removing it, or reports the deque is empty
+func (d *Deque[T]) Front() (T, bool) {
+	return d.At2(0)
+}
+
+// Back returns the element at the back without removing it, or reports the deque is empty
+func (d *Deque[T]) Back() (T, bool) {
+	return d.At2(d.size - 1)
+}
+
+// At2 returns element at idx from the front, or report empty element if idx <0 or >= Count
+func (d *Deque[T]) At2(idx int) (T, bool) {
+	if idx < 0 || idx >= d.size {
+		var empty T
+		return empty, false
+	}
+	return d.buffer[d.position(idx)], true
+}
+
+// At returns element at idx from the front, or empty if idx <0 or >= Count
+func (d *Deque[T]) At(idx int) T {
+	e, _ := d.At2(idx)
+	return e
+}
+
+// Set replaces the element at idx from the front. It returns false if idx <0 or >= Count.
+func (d *Deque[T]) Set(idx int, e T) bool {
+	if idx < 0 || idx >= d.size {
+		return false
+	}
+	d.buffer[d.position(idx)] = e
+	return true
+}
+
+// Count returns the number of elements
+func (d *Deque[T]) Count() int {
+	return d.size
+}
+
+// IsEmpty returns true if the deque has no elements
+func (d *Deque[T]) IsEmpty() bool {
+	return d.size == 0
+}
+
+// Clear removes all the elements
+func (d *Deque[T]) Clear() {
+	d.buffer = nil
+	d.head = 0
+	d.size = 0
+}
+
+// Range calls fn with each element from the front to the back. Iteration stops if fn returns false.
+func (d *Deque[T]) Range(fn func(T) bool) {
+	for idx := 0; idx < d.size; idx++ {
+		if !fn(d.buffer[d.position(idx)]) {
+			return
+		}
+	}
+}
+
+// Values returns a slice with the elements from the front to the back
+func (d *Deque[T]) Values() []T {
+	values := make([]T, 0, d.size)
+	d.Range(func(e T) bool {
+		values = append(values, e)
+		return true
+	})
+	return values
+}
+
+// String returns a string representation of the deque, from the front to the back
+func (d *Deque[T]) String() string {
+	var builder strings.Builder
+	builder.WriteString("[")
+	for idx := 0; idx < d.size; idx++ {
+		if idx > 0 {
+			builder.WriteString(" ")
+		}
+		fmt.Fprintf(&builder, "%v", d.buffer[d.position(idx)])
+	}
+	builder.WriteString("]")
+	return builder.String()
+}
+
+// MarshalJSON writes the deque as an array from the front to the back
+func (d *Deque[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Values())
+}
+
+// UnmarshalJSON reads the deque from an array, the first element at the front
+func (d *Deque[T]) UnmarshalJSON(data []byte) error {
+	var elements []T
+
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return err
+	}
+
+	*d = *DequeOf(elements...)
+
+	return nil
+}
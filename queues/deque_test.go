@@ -0,0 +1,88 @@
+package queues
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeque_push_and_pop_at_both_ends(t *testing.T) {
+	var d Deque[int]
+
+	d.PushBack(2)
+	d.PushBack(3)
+	d.PushFront(1)
+	d.PushFront(0)
+
+	assert.Equal(t, []int{0, 1, 2, 3}, d.Values())
+
+	front, _ := d.PopFront()
+	back, _ := d.PopBack()
+	assert.Equal(t, 0, front)
+	assert.Equal(t, 3, back)
+	assert.Equal(t, 2, d.Count())
+}
+
+func TestDeque_empty_pops_report_not_found(t *testing.T) {
+	d := NewDeque[string](0)
+
+	_, foundFront := d.PopFront()
+	_, foundBack := d.Back()
+
+	assert.False(t, foundFront)
+	assert.False(t, foundBack)
+	assert.True(t, d.IsEmpty())
+}
+
+func TestDeque_grows_and_shrinks_keeping_order(t *testing.T) {
+	d := NewDeque[int](0)
+
+	// wrap around the ring buffer before it grows
+	for i := 0; i < 5; i++ {
+		d.PushBack(i)
+	}
+	for i := 0; i < 3; i++ {
+		d.PopFront()
+	}
+	for i := 5; i < 1000; i++ {
+		d.PushBack(i)
+	}
+
+	assert.Equal(t, 997, d.Count())
+	assert.Equal(t, 3, d.At(0))
+	assert.Equal(t, 999, d.At(996))
+
+	for i := 3; i < 990; i++ {
+		v, _ := d.PopFront()
+		assert.Equal(t, i, v)
+	}
+	assert.Equal(t, []int{990, 991, 992, 993, 994, 995, 996, 997, 998, 999}, d.Values())
+	assert.LessOrEqual(t, len(d.buffer), 64)
+}
+
+func TestDeque_indexed_access(t *testing.T) {
+	d := DequeOf("a", "b", "c")
+	d.PushFront("z")
+
+	assert.Equal(t, "a", d.At(1))
+	assert.True(t, d.Set(1, "A"))
+	assert.False(t, d.Set(4, "x"))
+	_, found := d.At2(-1)
+	assert.False(t, found)
+	assert.Equal(t, "[z A b c]", d.String())
+}
+
+func TestDeque_json(t *testing.T) {
+	data, err := json.Marshal(DequeOf(1, 2, 3))
+	assert.Nil(t, err)
+	assert.Equal(t, `[1,2,3]`, string(data))
+
+	empty, _ := json.Marshal(&Deque[int]{})
+	assert.Equal(t, `[]`, string(empty))
+
+	var d Deque[int]
+	assert.Nil(t, json.Unmarshal([]byte(`[4,5]`), &d))
+	front, _ := d.Front()
+	assert.Equal(t, 4, front)
+}
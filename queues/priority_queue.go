@@ -0,0 +1,233 @@
+package queues
+
+import (
+	"github.com/totemcaf/gollections/types"
+	"golang.org/x/exp/constraints"
+)
+
+// Less returns true if a must come out of a PriorityQueue before b
+type Less[T any] func(a, b T) bool
+
+// Ascending orders values from the smallest to the largest
+func Ascending[T constraints.Ordered]() Less[T] {
+	return func(a, b T) bool { return a < b }
+}
+
+// Descending orders values from the largest to the smallest
+func Descending[T constraints.Ordered]() Less[T] {
+	return func(a, b T) bool { return a > b }
+}
+
+// ByCompare orders Comparable values from the smallest to the largest
+func ByCompare[T types.Comparable[T]]() Less[T] {
+	return func(a, b T) bool { return a.Compare(b) < 0 }
+}
+
+// Reverse inverts an order
+func Reverse[T any](less Less[T]) Less[T] {
+	return func(a, b T) bool { return less(b, a) }
+}
+
+// Handle identifies an element in a PriorityQueue, to update or remove it. It is valid while the element is in the
+// queue.
+type Handle[T any] struct {
+	value T
+	index int
+	queue *PriorityQueue[T]
+}
+
+// Value returns the value of the element
+func (h *Handle[T]) Value() T {
+	return h.value
+}
+
+// PriorityQueue is a binary heap that returns its elements in the order given by a Less function, the first
+// one is the one that is less than all the others.
+type PriorityQueue[T any] struct {
+	items []*Handle[T]
+	less  Less[T]
+}
+
+// NewPriorityQueue creates an empty queue that returns the elements in the order of less
+func NewPriorityQueue[T any](less Less[T]) *PriorityQueue[T] {
+	return &PriorityQueue[T]{less: less}
+}
+
+// NewMinQueue creates an empty queue that returns the smallest element first
+func NewMinQueue[T constraints.Ordered]() *PriorityQueue[T] {
+	return NewPriorityQueue(Ascending[T]())
+}
+
+// NewMaxQueue creates an empty queue that returns the largest element first
+func NewMaxQueue[T constraints.Ordered]() *PriorityQueue[T] {
+	return NewPriorityQueue(Descending[T]())
+}
+
+// Push adds an element and returns its handle
+func (q *PriorityQueue[T]) Push(value T) *Handle[T] {
+	h := &Handle[T]{value, len(q.items), q}
+	q.items = append(q.items, h)
+	q.up(h.index)
+	return h
+}
+
+// PushAll adds all the elements
+func (q *PriorityQueue[T]) PushAll(values ...T) {
+	for _, v := range values {
+		q.items = append(q.items, &Handle[T]{v, len(q.items), q})
+	}
+	q.heapify()
+}
+
+// Pop removes and returns the first element, or reports the queue is empty
+func (q *PriorityQueue[T]) Pop() (T, bool) {
+	if len(q.items) == 0 {
+		var empty T
+		return empty, false
+	}
+	return q.removeAt(0).value, true
+}
+
+// Peek returns the first element without removing it, or reports the queue is empty
+func (q *PriorityQueue[T]) Peek() (T, bool) {
+	if len(q.items) == 0 {
+		var empty T
+		return empty, false
+	}
+	return q.items[0].value, true
+}
+
+// Contains returns true if the element of the handle is in the queue
+func (q *PriorityQueue[T]) Contains(h *Handle[T]) bool {
+	return h != nil && h.queue == q && h.index >= 0
+}
+
+// Update changes the value of the element of the handle, moving it to its new place. It returns false if the
+// element is not in the queue.
+func (q *PriorityQueue[T]) Update(h *Handle[T], value T) bool {
+	if !q.Contains(h) {
+		return false
+	}
+	h.value = value
+	q.fix(h.index)
+	return true
+}
+
+// Remove removes the element of the handle. It returns false if the element is not in the queue.
+func (q *PriorityQueue[T]) Remove(h *Handle[T]) bool {
+	if !q.Contains(h) {
+		return false
+	}
+	q.removeAt(h.index)
+	return true
+}
+
+// Merge moves all the elements of the other queue to this one, leaving the other empty. The handles of the moved
+// elements are now handles of this queue. Elements are ordered with the Less of this queue.
+func (q *PriorityQueue[T]) Merge(other *PriorityQueue[T]) {
+	if other == q {
+		return
+	}
+	for _, h := range other.items {
+		h.index = len(q.items)
+		h.queue = q
+		q.items = append(q.items, h)
+	}
+	other.items = nil
+	q.heapify()
+}
+
+// Count returns the number of elements
+func (q *PriorityQueue[T]) Count() int {
+	return len(q.items)
+}
+
+// IsEmpty returns true if the queue has no elements
+func (q *PriorityQueue[T]) IsEmpty() bool {
+	return len(q.items) == 0
+}
+
+// Clear removes all the elements
+func (q *PriorityQueue[T]) Clear() {
+	for _, h := range q.items {
+		h.index = -1
+	}
+	q.items = nil
+}
+
+// Values returns a slice with the elements in no particular order
+func (q *PriorityQueue[T]) Values() []T {
+	values := make([]T, len(q.items))
+	for idx, h := range q.items {
+		values[idx] = h.value
+	}
+	return values
+}
+
+func (q *PriorityQueue[T]) removeAt(idx int) *Handle[T] {
+	h := q.items[idx]
+	last := len(q.items) - 1
+
+	q.swap(idx, last)
+	q.items[last] = nil
+	q.items = q.items[:last]
+	if idx < last {
+		q.fix(idx)
+	}
+
+	h.index = -1
+	return h
+}
+
+func (q *PriorityQueue[T]) heapify() {
+	for idx := len(q.items)/2 - 1; idx >= 0; idx-- {
+		q.down(idx)
+	}
+}
+
+func (q *PriorityQueue[T]) fix(idx int) {
+	if !q.down(idx) {
+		q.up(idx)
+	}
+}
+
+func (q *PriorityQueue[T]) swap(i, j int) {
+	q.items[i], q.items[j] = q.items[j], q.items[i]
+	q.items[i].index = i
+	q.items[j].index = j
+}
+
+func (q *PriorityQueue[T]) before(i, j int) bool {
+	return q.less(q.items[i].value, q.items[j].value)
+}
+
+func (q *PriorityQueue[T]) up(idx int) {
+	for idx > 0 {
+		parent := (idx - 1) / 2
+		if !q.before(idx, parent) {
+			return
+		}
+		q.swap(idx, parent)
+		idx = parent
+	}
+}
+
+// down moves the element at idx down the heap and returns true if it moved
+func (q *PriorityQueue[T]) down(idx int) bool {
+	start := idx
+	for {
+		first := 2*idx + 1
+		if first >= len(q.items) {
+			break
+		}
+		if second := first + 1; second < len(q.items) && q.before(second, first) {
+			first = second
+		}
+		if !q.before(first, idx) {
+			break
+		}
+		q.swap(idx, first)
+		idx = first
+	}
+	return idx > start
+}
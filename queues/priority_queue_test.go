@@ -0,0 +1,137 @@
+package queues
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func popAll[T any](q *PriorityQueue[T]) []T {
+	var values []T
+	for v, ok := q.Pop(); ok; v, ok = q.Pop() {
+		values = append(values, v)
+	}
+	return values
+}
+
+func TestPriorityQueue_pops_in_order(t *testing.T) {
+	random := rand.New(rand.NewSource(5))
+	values := make([]int, 500)
+	for idx := range values {
+		values[idx] = random.Intn(100)
+	}
+
+	minQueue := NewMinQueue[int]()
+	maxQueue := NewMaxQueue[int]()
+	for _, v := range values {
+		minQueue.Push(v)
+	}
+	maxQueue.PushAll(values...)
+
+	sort.Ints(values)
+	assert.Equal(t, values, popAll(minQueue))
+	sort.Sort(sort.Reverse(sort.IntSlice(values)))
+	assert.Equal(t, values, popAll(maxQueue))
+}
+
+func TestPriorityQueue_empty(t *testing.T) {
+	q := NewMinQueue[string]()
+
+	_, popped := q.Pop()
+	_, peeked := q.Peek()
+
+	assert.False(t, popped)
+	assert.False(t, peeked)
+	assert.True(t, q.IsEmpty())
+}
+
+type job struct {
+	name     string
+	deadline time.Time
+}
+
+func (j job) Compare(other job) int {
+	switch {
+	case j.deadline.Before(other.deadline):
+		return -1
+	case j.deadline.After(other.deadline):
+		return 1
+	default:
+		return 0
+	}
+}
+
+func TestPriorityQueue_Update_and_Remove_through_handles(t *testing.T) {
+	now := time.Now()
+	q := NewPriorityQueue(ByCompare[job]())
+
+	report := q.Push(job{"report", now.Add(3 * time.Hour)})
+	backup := q.Push(job{"backup", now.Add(2 * time.Hour)})
+	q.Push(job{"cleanup", now.Add(time.Hour)})
+
+	assert.True(t, q.Update(report, job{"report", now}))
+	assert.True(t, q.Remove(backup))
+	assert.False(t, q.Remove(backup))
+
+	first, _ := q.Pop()
+	assert.Equal(t, "report", first.name)
+	assert.False(t, q.Update(report, job{"report", now}))
+	assert.Equal(t, 1, q.Count())
+}
+
+func TestPriorityQueue_Merge_moves_handles(t *testing.T) {
+	a := NewMinQueue[int]()
+	b := NewMinQueue[int]()
+	a.PushAll(5, 1, 9)
+	h := b.Push(7)
+	b.Push(3)
+
+	a.Merge(b)
+
+	assert.True(t, b.IsEmpty())
+	assert.False(t, b.Contains(h))
+	assert.True(t, a.Update(h, 0))
+	assert.Equal(t, []int{0, 1, 3, 5, 9}, popAll(a))
+}
+
+// Dijkstra is the typical use of decrease-key
+func TestPriorityQueue_shortest_paths(t *testing.T) {
+	type edge struct{ to, weight int }
+	graph := map[int][]edge{
+		0: {{1, 4}, {2, 1}},
+		2: {{1, 2}, {3, 5}},
+		1: {{3, 1}},
+	}
+
+	type visit struct{ node, distance int }
+	q := NewPriorityQueue(func(a, b visit) bool { return a.distance < b.distance })
+	handles := map[int]*Handle[visit]{0: q.Push(visit{0, 0})}
+	distances := map[int]int{}
+
+	for v, ok := q.Pop(); ok; v, ok = q.Pop() {
+		distances[v.node] = v.distance
+		for _, e := range graph[v.node] {
+			if _, done := distances[e.to]; done {
+				continue
+			}
+			next := visit{e.to, v.distance + e.weight}
+			if h, queued := handles[e.to]; !queued {
+				handles[e.to] = q.Push(next)
+			} else if next.distance < h.Value().distance {
+				q.Update(h, next)
+			}
+		}
+	}
+
+	assert.Equal(t, map[int]int{0: 0, 1: 3, 2: 1, 3: 4}, distances)
+}
+
+func TestReverse(t *testing.T) {
+	q := NewPriorityQueue(Reverse(Ascending[string]()))
+	q.PushAll("a", "c", "b")
+
+	assert.Equal(t, []string{"c", "b", "a"}, popAll(q))
+}
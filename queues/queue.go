@@ -0,0 +1,67 @@
+package queues
+
+// Queue is a FIFO container: elements are dequeued in the order they were enqueued.
+// The zero value is an empty queue ready to use.
+type Queue[T any] struct {
+	deque Deque[T]
+}
+
+// NewQueue creates an empty queue
+func NewQueue[T any]() *Queue[T] {
+	return &Queue[T]{}
+}
+
+// QueueOf creates a queue with the given elements enqueued in order, so the first one is at the front
+func QueueOf[T any](es ...T) *Queue[T] {
+	return &Queue[T]{*DequeOf(es...)}
+}
+
+// Enqueue adds an element at the back
+func (q *Queue[T]) Enqueue(e T) {
+	q.deque.PushBack(e)
+}
+
+// Dequeue removes and returns the element at the front, or reports the queue is empty
+func (q *Queue[T]) Dequeue() (T, bool) {
+	return q.deque.PopFront()
+}
+
+// Peek returns the element at the front without removing it, or reports the queue is empty
+func (q *Queue[T]) Peek() (T, bool) {
+	return q.deque.Front()
+}
+
+// Count returns the number of elements
+func (q *Queue[T]) Count() int {
+	return q.deque.Count()
+}
+
+// IsEmpty returns true if the queue has no elements
+func (q *Queue[T]) IsEmpty() bool {
+	return q.deque.IsEmpty()
+}
+
+// Clear removes all the elements
+func (q *Queue[T]) Clear() {
+	q.deque.Clear()
+}
+
+// Values returns a slice with the elements from the front to the back
+func (q *Queue[T]) Values() []T {
+	return q.deque.Values()
+}
+
+// String returns a string representation of the queue, from the front to the back
+func (q *Queue[T]) String() string {
+	return q.deque.String()
+}
+
+// MarshalJSON writes the queue as an array from the front to the back
+func (q *Queue[T]) MarshalJSON() ([]byte, error) {
+	return q.deque.MarshalJSON()
+}
+
+// UnmarshalJSON reads the queue from an array, the first element at the front
+func (q *Queue[T]) UnmarshalJSON(data []byte) error {
+	return q.deque.UnmarshalJSON(data)
+}
@@ -0,0 +1,31 @@
+package queues
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueue_is_first_in_first_out(t *testing.T) {
+	var q Queue[int]
+	q.Enqueue(1)
+	q.Enqueue(2)
+
+	front, _ := q.Peek()
+	first, _ := q.Dequeue()
+	second, _ := q.Dequeue()
+	_, found := q.Dequeue()
+
+	assert.Equal(t, 1, front)
+	assert.Equal(t, 1, first)
+	assert.Equal(t, 2, second)
+	assert.False(t, found)
+}
+
+func TestQueue_json_is_front_to_back(t *testing.T) {
+	data, err := json.Marshal(QueueOf("a", "b"))
+
+	assert.Nil(t, err)
+	assert.Equal(t, `["a","b"]`, string(data))
+}
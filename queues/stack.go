@@ -0,0 +1,67 @@
+package queues
+
+// Stack is a LIFO container: the last element pushed is the first one popped.
+// The zero value is an empty stack ready to use.
+type Stack[T any] struct {
+	deque Deque[T]
+}
+
+// NewStack creates an empty stack
+func NewStack[T any]() *Stack[T] {
+	return &Stack[T]{}
+}
+
+// StackOf creates a stack with the given elements pushed in order, so the last one is at the top
+func StackOf[T any](es ...T) *Stack[T] {
+	return &Stack[T]{*DequeOf(es...)}
+}
+
+// Push adds an element at the top
+func (s *Stack[T]) Push(e T) {
+	s.deque.PushBack(e)
+}
+
+// Pop removes and returns the element at the top, or reports the stack is empty
+func (s *Stack[T]) Pop() (T, bool) {
+	return s.deque.PopBack()
+}
+
+// Peek returns the element at the top without removing it, or reports the stack is empty
+func (s *Stack[T]) Peek() (T, bool) {
+	return s.deque.Back()
+}
+
+// Count returns the number of elements
+func (s *Stack[T]) Count() int {
+	return s.deque.Count()
+}
+
+// IsEmpty returns true if the stack has no elements
+func (s *Stack[T]) IsEmpty() bool {
+	return s.deque.IsEmpty()
+}
+
+// Clear removes all the elements
+func (s *Stack[T]) Clear() {
+	s.deque.Clear()
+}
+
+// Values returns a slice with the elements from the bottom to the top
+func (s *Stack[T]) Values() []T {
+	return s.deque.Values()
+}
+
+// String returns a string representation of the stack, from the bottom to the top
+func (s *Stack[T]) String() string {
+	return s.deque.String()
+}
+
+// MarshalJSON writes the stack as an array from the bottom to the top
+func (s *Stack[T]) MarshalJSON() ([]byte, error) {
+	return s.deque.MarshalJSON()
+}
+
+// UnmarshalJSON reads the stack from an array, the last element at the top
+func (s *Stack[T]) UnmarshalJSON(data []byte) error {
+	return s.deque.UnmarshalJSON(data)
+}
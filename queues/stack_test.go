@@ -0,0 +1,33 @@
+package queues
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStack_is_last_in_first_out(t *testing.T) {
+	s := StackOf(1, 2)
+	s.Push(3)
+
+	top, _ := s.Peek()
+	first, _ := s.Pop()
+	second, _ := s.Pop()
+
+	assert.Equal(t, 3, top)
+	assert.Equal(t, 3, first)
+	assert.Equal(t, 2, second)
+	assert.Equal(t, 1, s.Count())
+}
+
+func TestStack_json_is_bottom_to_top(t *testing.T) {
+	var s Stack[string]
+	assert.Nil(t, json.Unmarshal([]byte(`["a","b"]`), &s))
+
+	top, _ := s.Pop()
+	data, _ := json.Marshal(&s)
+
+	assert.Equal(t, "b", top)
+	assert.Equal(t, `["a"]`, string(data))
+}
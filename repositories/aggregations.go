@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"github.com/totemcaf/gollections/types"
+	"golang.org/x/exp/constraints"
+)
+
+// Number is a type that can be added
+type Number interface {
+	constraints.Integer | constraints.Float
+}
+
+// These are implemented as functions because GO generics does not support (yet) the use of type parameters in
+// methods signatures. All of them compute the result under a single read lock. A nil filter selects all entities.
+
+// CountBy returns how many entities belong to each group
+func CountBy[Key comparable, Entity any, Group comparable](
+	r *InMemoryRepository[Key, Entity],
+	group types.Mapper[Entity, Group],
+	filter types.Predicate[Entity],
+) map[Group]int {
+	counts := make(map[Group]int)
+
+	r.forEach(filter, func(entity Entity) {
+		counts[group(entity)]++
+	})
+
+	return counts
+}
+
+// GroupBy returns the entities of each group
+func GroupBy[Key comparable, Entity any, Group comparable](
+	r *InMemoryRepository[Key, Entity],
+	group types.Mapper[Entity, Group],
+	filter types.Predicate[Entity],
+) map[Group][]Entity {
+	groups := make(map[Group][]Entity)
+
+	r.forEach(filter, func(entity Entity) {
+		g := group(entity)
+		groups[g] = append(groups[g], r.clone(entity))
+	})
+
+	return groups
+}
+
+// SumBy returns the sum of the values of the entities of each group
+func SumBy[Key comparable, Entity any, Group comparable, N Number](
+	r *InMemoryRepository[Key, Entity],
+	group types.Mapper[Entity, Group],
+	value types.Mapper[Entity, N],
+	filter types.Predicate[Entity],
+) map[Group]N {
+	sums := make(map[Group]N)
+
+	r.forEach(filter, func(entity Entity) {
+		sums[group(entity)] += value(entity)
+	})
+
+	return sums
+}
+
+// MinBy returns the minimum of the values of the entities of each group
+func MinBy[Key comparable, Entity any, Group comparable, N constraints.Ordered](
+	r *InMemoryRepository[Key, Entity],
+	group types.Mapper[Entity, Group],
+	value types.Mapper[Entity, N],
+	filter types.Predicate[Entity],
+) map[Group]N {
+	return extremeBy(r, group, value, filter, func(a, b N) bool { return a < b })
+}
+
+// MaxBy returns the maximum of the values of the entities of each group
+func MaxBy[Key comparable, Entity any, Group comparable, N constraints.Ordered](
+	r *InMemoryRepository[Key, Entity],
+	group types.Mapper[Entity, Group],
+	value types.Mapper[Entity, N],
+	filter types.Predicate[Entity],
+) map[Group]N {
+	return extremeBy(r, group, value, filter, func(a, b N) bool { return a > b })
+}
+
+func extremeBy[Key comparable, Entity any, Group comparable, N constraints.Ordered](
+	r *InMemoryRepository[Key, Entity],
+	group types.Mapper[Entity, Group],
+	value types.Mapper[Entity, N],
+	filter types.Predicate[Entity],
+	better func(a, b N) bool,
+) map[Group]N {
+	extremes := make(map[Group]N)
+
+	r.forEach(filter, func(entity Entity) {
+		g, v := group(entity), value(entity)
+		if current, found := extremes[g]; !found || better(v, current) {
+			extremes[g] = v
+		}
+	})
+
+	return extremes
+}
+
+// forEach calls fn with all the entities that satisfy filter while holding the read lock
+func (r *InMemoryRepository[Key, Entity]) forEach(filter types.Predicate[Entity], fn types.Function1[Entity]) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	for _, entity := range r.elementsById {
+		if filter == nil || filter(entity) {
+			fn(entity)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/totemcaf/gollections/maps"
+)
+
+type sale struct {
+	Id      string
+	Country string
+	Amount  float64
+}
+
+func newSalesRepo() *InMemoryRepository[string, sale] {
+	repo := &InMemoryRepository[string, sale]{GetKey: func(s sale) string { return s.Id }}
+	_, _ = repo.CreateAll(
+		sale{"s1", "AR", 10},
+		sale{"s2", "AR", 30},
+		sale{"s3", "UY", 5},
+		sale{"s4", "BR", 100},
+	)
+	return repo
+}
+
+func country(s sale) string { return s.Country }
+func amount(s sale) float64 { return s.Amount }
+func notBrazil(s sale) bool { return s.Country != "BR" }
+
+func Test_CountBy_counts_each_group(t *testing.T) {
+	counts := CountBy(newSalesRepo(), country, nil)
+
+	assert.Equal(t, map[string]int{"AR": 2, "UY": 1, "BR": 1}, counts)
+}
+
+func Test_GroupBy_applies_filter(t *testing.T) {
+	groups := GroupBy(newSalesRepo(), country, notBrazil)
+
+	assert.ElementsMatch(t, []string{"AR", "UY"}, maps.Keys(groups))
+	assert.Len(t, groups["AR"], 2)
+}
+
+func Test_SumBy_MinBy_MaxBy(t *testing.T) {
+	repo := newSalesRepo()
+
+	assert.Equal(t, map[string]float64{"AR": 40, "UY": 5}, SumBy(repo, country, amount, notBrazil))
+	assert.Equal(t, map[string]float64{"AR": 10, "UY": 5, "BR": 100}, MinBy(repo, country, amount, nil))
+	assert.Equal(t, map[string]float64{"AR": 30, "UY": 5, "BR": 100}, MaxBy(repo, country, amount, nil))
+}
@@ -0,0 +1,217 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/totemcaf/gollections/types"
+)
+
+var batchAborted = errors.New("batch aborted, other element failed")
+
+// CreateAll adds all the entities taking the lock once. It is atomic: if any entity has an invalid key or a key
+// that is already in the repository or repeated in the batch, or it is rejected by Validate or BeforeCreate,
+// no entity is added. AfterCreate runs once all the entities are stored. If it fails for any entity, or storing an
+// entity fails part-way, for example when the write-ahead log cannot be written, the entities already stored are
+// removed again, watchers receive a Deleted event for them and the log is rewritten without them.
+// Entities without key get a generated one as in Create.
+// The errors are reported for each entity in the same order. When the batch fails, the entities without their own
+// error report batchAborted.
+func (r *InMemoryRepository[Key, Entity]) CreateAll(entities ...Entity) ([]Entity, []error) {
+	return r.CreateAllCtx(context.Background(), entities...)
+}
+
+// CreateAllCtx is like CreateAll but gives up if ctx is done before the operation starts
+func (r *InMemoryRepository[Key, Entity]) CreateAllCtx(ctx context.Context, entities ...Entity) ([]Entity, []error) {
+	if err := r.Faults.inject(ctx, OpCreateAll, nil); err != nil {
+		return entities, failAll(len(entities), err)
+	}
+
+	if err := r.lockCtx(ctx); err != nil {
+		return entities, failAll(len(entities), err)
+	}
+	defer r.lock.Unlock()
+	r.init()
+
+	results := make([]Entity, len(entities))
+	errs := make([]error, len(entities))
+	keys := make([]Key, len(entities))
+	inBatch := make(map[Key]struct{}, len(entities))
+	failed := false
+
+	for idx, entity := range entities {
+		entity, key, err := r.assignKey(entity, inBatch)
+		results[idx] = entity
+		keys[idx] = key
+
+		if err != nil {
+			errs[idx] = err
+		} else if !r.AllowEmptyKey && key == r.emptyKey {
+			errs[idx] = invalidKey
+		} else if r.keyInUse(key) {
+			errs[idx] = duplicateKey
+		} else if _, found := inBatch[key]; found {
+			errs[idx] = duplicateKey
+		}
+
+		inBatch[key] = struct{}{}
+		failed = failed || errs[idx] != nil
+	}
+
+	if failed {
+		return results, abortBatch(errs)
+	}
+
+	for idx, entity := range results {
+		errs[idx] = r.prepareCreate(entity)
+		failed = failed || errs[idx] != nil
+	}
+
+	if failed {
+		return results, abortBatch(errs)
+	}
+
+	for idx, entity := range results {
+		results[idx] = r.stampCreated(entity)
+	}
+
+	for idx, entity := range results {
+		if errs[idx] = r.apply(Event[Key, Entity]{Type: Created, Key: keys[idx], New: entity}); errs[idx] != nil {
+			r.undoCreated(keys[:idx])
+			return results, abortBatch(errs)
+		}
+	}
+
+	for idx, entity := range results {
+		if errs[idx] = r.afterCreate(entity); errs[idx] != nil {
+			r.undoCreated(keys)
+			return results, abortBatch(errs)
+		}
+	}
+
+	return results, errs
+}
+
+// Upsert creates the entity or replaces it if its key is already in the repository.
+// An entity without key is created with a generated one as in Create.
+func (r *InMemoryRepository[Key, Entity]) Upsert(entity Entity) (Entity, error) {
+	return r.UpsertCtx(context.Background(), entity)
+}
+
+// UpsertCtx is like Upsert but gives up if ctx is done before the operation starts
+func (r *InMemoryRepository[Key, Entity]) UpsertCtx(ctx context.Context, entity Entity) (Entity, error) {
+	if err := r.Faults.inject(ctx, OpUpsert, r.GetKey(entity)); err != nil {
+		return entity, err
+	}
+
+	if err := r.lockCtx(ctx); err != nil {
+		return entity, err
+	}
+	defer r.lock.Unlock()
+	r.init()
+
+	return r.upsert(entity)
+}
+
+// UpsertAll creates or replaces all the entities taking the lock once. Errors are reported for each entity in
+// the same order, an entity that fails does not prevent the others from being stored.
+func (r *InMemoryRepository[Key, Entity]) UpsertAll(entities ...Entity) ([]Entity, []error) {
+	return r.UpsertAllCtx(context.Background(), entities...)
+}
+
+// UpsertAllCtx is like UpsertAll but gives up if ctx is done before the operation starts
+func (r *InMemoryRepository[Key, Entity]) UpsertAllCtx(ctx context.Context, entities ...Entity) ([]Entity, []error) {
+	if err := r.Faults.inject(ctx, OpUpsertAll, nil); err != nil {
+		return entities, failAll(len(entities), err)
+	}
+
+	if err := r.lockCtx(ctx); err != nil {
+		return entities, failAll(len(entities), err)
+	}
+	defer r.lock.Unlock()
+	r.init()
+
+	results := make([]Entity, len(entities))
+	errs := make([]error, len(entities))
+
+	for idx, entity := range entities {
+		results[idx], errs[idx] = r.upsert(entity)
+	}
+
+	return results, errs
+}
+
+func (r *InMemoryRepository[Key, Entity]) upsert(entity Entity) (Entity, error) {
+	entity, key, err := r.assignKey(entity, nil)
+	if err != nil {
+		return entity, err
+	}
+	if !r.AllowEmptyKey && key == r.emptyKey {
+		return entity, invalidKey
+	}
+
+	if old, found := r.elementsById[key]; found {
+		return r.update(key, old, entity)
+	}
+
+	if r.keyInUse(key) {
+		return entity, duplicateKey
+	}
+
+	return r.create(key, entity)
+}
+
+// DeleteBy removes all the entities that satisfy predicate taking the lock once. Returns how many were removed.
+// Entities rejected by BeforeDelete are kept. It cannot report errors: a fault injected by Faults removes nothing
+// and returns 0. Use DeleteByCtx to get the error.
+func (r *InMemoryRepository[Key, Entity]) DeleteBy(predicate types.Predicate[Entity]) int {
+	count, _ := r.DeleteByCtx(context.Background(), predicate)
+	return count
+}
+
+// DeleteByCtx is like DeleteBy but gives up if ctx is done before the operation starts
+func (r *InMemoryRepository[Key, Entity]) DeleteByCtx(ctx context.Context, predicate types.Predicate[Entity]) (int, error) {
+	if err := r.Faults.inject(ctx, OpDeleteBy, nil); err != nil {
+		return 0, err
+	}
+
+	if err := r.lockCtx(ctx); err != nil {
+		return 0, err
+	}
+	defer r.lock.Unlock()
+	r.init()
+
+	count := 0
+
+	for key, entity := range r.elementsById {
+		if predicate(entity) && r.remove(key, entity) == nil {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// abortBatch reports batchAborted for all the entities that did not fail by themselves
+func abortBatch(errs []error) []error {
+	for idx := range errs {
+		if errs[idx] == nil {
+			errs[idx] = batchAborted
+		}
+	}
+	return errs
+}
+
+// failAll reports the same error for all the entities
+func failAll(count int, err error) []error {
+	errs := make([]error, count)
+	for idx := range errs {
+		errs[idx] = err
+	}
+	return errs
+}
+
+// DeleteAll removes all the entities. Returns how many were removed.
+func (r *InMemoryRepository[Key, Entity]) DeleteAll() int {
+	return r.DeleteBy(func(Entity) bool { return true })
+}
@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_CreateAll_adds_all_entities(t *testing.T) {
+	repo := newRepo()
+
+	_, errs := repo.CreateAll(&entity{"a-key-001", 1}, &entity{"a-key-002", 2}, &entity{"a-key-003", 3})
+
+	assert.Equal(t, []error{nil, nil, nil}, errs)
+	assert.Equal(t, 3, repo.TotalCount())
+}
+
+func Test_CreateAll_adds_nothing_if_a_key_is_duplicated(t *testing.T) {
+	repo := newRepo()
+	_, _ = repo.Create(&entity{"a-key-002", 2})
+
+	_, errs := repo.CreateAll(&entity{"a-key-001", 1}, &entity{"a-key-002", 2}, &entity{"a-key-001", 3})
+
+	assert.Equal(t, []error{batchAborted, duplicateKey, duplicateKey}, errs)
+	assert.Equal(t, 1, repo.TotalCount())
+}
+
+func Test_UpsertAll_creates_and_replaces_entities(t *testing.T) {
+	repo := newRepo()
+	_, _ = repo.Create(&entity{"a-key-001", 1})
+
+	_, errs := repo.UpsertAll(&entity{"a-key-001", 11}, &entity{"", 0}, &entity{"a-key-002", 2})
+
+	assert.Equal(t, []error{nil, invalidKey, nil}, errs)
+	assert.Equal(t, 2, repo.TotalCount())
+	found, _ := repo.FindByID("a-key-001")
+	assert.Equal(t, 11, found.Value)
+}
+
+func Test_DeleteBy_removes_matching_entities(t *testing.T) {
+	repo := newRepo()
+	_, _ = repo.CreateAll(&entity{"a-key-001", 4200}, &entity{"a-key-002", 42}, &entity{"a-key-003", 179})
+
+	count := repo.DeleteBy(func(e *entity) bool { return e.Value > 100 })
+
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 1, repo.TotalCount())
+}
+
+func Test_DeleteAll_removes_all_entities(t *testing.T) {
+	repo := newRepo()
+	_, _ = repo.CreateAll(&entity{"a-key-001", 4200}, &entity{"a-key-002", 42})
+
+	count := repo.DeleteAll()
+
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 0, repo.TotalCount())
+}
+
+// failingCodec is a JSONCodec whose encoders fail on the failAt-th record written through any of them
+type failingCodec struct {
+	JSONCodec
+	written *int
+	failAt  int
+}
+
+func (c failingCodec) NewEncoder(w io.Writer) Encoder {
+	return failingEncoder{Encoder: c.JSONCodec.NewEncoder(w), codec: c}
+}
+
+type failingEncoder struct {
+	Encoder
+	codec failingCodec
+}
+
+func (e failingEncoder) Encode(v any) error {
+	*e.codec.written++
+	if *e.codec.written == e.codec.failAt {
+		return errors.New("disk full")
+	}
+	return e.Encoder.Encode(v)
+}
+
+func Test_CreateAll_removes_stored_entities_if_the_log_fails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "repo.log")
+
+	repo := newRepo()
+	repo.Codec = failingCodec{written: new(int), failAt: 2}
+	assert.Nil(t, repo.OpenLog(path, 0))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := repo.Watch(ctx, nil)
+
+	_, errs := repo.CreateAll(&entity{"a-key-001", 1}, &entity{"a-key-002", 2}, &entity{"a-key-003", 3})
+
+	assert.Equal(t, batchAborted, errs[0])
+	assert.EqualError(t, errs[1], "disk full")
+	assert.Equal(t, batchAborted, errs[2])
+	assert.Equal(t, 0, repo.TotalCount())
+	assert.Equal(t, Created, (<-events).Type)
+	assert.Equal(t, Event[string, *entity]{Type: Deleted, Key: "a-key-001", Old: &entity{"a-key-001", 1}}, <-events)
+	assert.Nil(t, repo.CloseLog())
+
+	restarted := newRepo()
+	assert.Nil(t, restarted.OpenLog(path, 0))
+	defer func() { _ = restarted.CloseLog() }()
+
+	assert.Equal(t, 0, restarted.TotalCount())
+}
@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"reflect"
+	"time"
+	"unsafe"
+
+	"github.com/totemcaf/gollections/types"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// clone returns a copy of the entity that shares no memory with it when the repository isolates entities.
+// Entities that implement types.Cloneable are copied with Clone, others with a reflection based deep copy.
+// A nil entity, as the missing side of an event, is returned as is.
+func (r *InMemoryRepository[Key, Entity]) clone(entity Entity) Entity {
+	if !r.Isolate || isNil(entity) {
+		return entity
+	}
+
+	if cloneable, ok := any(entity).(types.Cloneable[Entity]); ok {
+		return cloneable.Clone()
+	}
+
+	return deepCopy(entity)
+}
+
+// cloneAll clones all the entities when the repository isolates entities
+func (r *InMemoryRepository[Key, Entity]) cloneAll(entities []Entity) []Entity {
+	if r.Isolate {
+		for idx, entity := range entities {
+			entities[idx] = r.clone(entity)
+		}
+	}
+	return entities
+}
+
+// isNil returns true if the entity is a nil pointer, slice, map or interface
+func isNil[T any](value T) bool {
+	source := reflect.ValueOf(&value).Elem()
+
+	switch source.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map, reflect.Interface, reflect.Chan, reflect.Func:
+		return source.IsNil()
+	default:
+		return false
+	}
+}
+
+// deepCopy copies pointers, slices, maps, arrays, interfaces and structs (including unexported fields)
+// recursively. Channels, functions and time.Time values are shared.
+func deepCopy[T any](value T) T {
+	source := reflect.ValueOf(&value).Elem()
+	target := reflect.New(source.Type()).Elem()
+
+	copyValue(target, source, make(map[visitedPointer]reflect.Value))
+
+	return target.Interface().(T)
+}
+
+// visitedPointer identifies a copied pointer. The address alone is not enough: a pointer to a struct and a
+// pointer to its first field have the same address.
+type visitedPointer struct {
+	typ     reflect.Type
+	address uintptr
+}
+
+func copyValue(target, source reflect.Value, visited map[visitedPointer]reflect.Value) {
+	if source.Type() == timeType {
+		target.Set(source)
+		return
+	}
+
+	switch source.Kind() {
+	case reflect.Pointer:
+		if source.IsNil() {
+			return
+		}
+		pointer := visitedPointer{source.Type(), source.Pointer()}
+		if copied, found := visited[pointer]; found {
+			target.Set(copied)
+			return
+		}
+		copied := reflect.New(source.Type().Elem())
+		visited[pointer] = copied
+		copyValue(copied.Elem(), source.Elem(), visited)
+		target.Set(copied)
+
+	case reflect.Interface:
+		if source.IsNil() {
+			return
+		}
+		element := reflect.New(source.Elem().Type()).Elem()
+		copyValue(element, source.Elem(), visited)
+		target.Set(element)
+
+	case reflect.Struct:
+		if !source.CanAddr() {
+			// unexported fields can only be read from an addressable struct
+			addressable := reflect.New(source.Type()).Elem()
+			addressable.Set(source)
+			source = addressable
+		}
+		target.Set(source)
+		for idx := 0; idx < source.NumField(); idx++ {
+			copyValue(settable(target.Field(idx)), settable(source.Field(idx)), visited)
+		}
+
+	case reflect.Slice:
+		if source.IsNil() {
+			return
+		}
+		copied := reflect.MakeSlice(source.Type(), source.Len(), source.Cap())
+		for idx := 0; idx < source.Len(); idx++ {
+			copyValue(copied.Index(idx), source.Index(idx), visited)
+		}
+		target.Set(copied)
+
+	case reflect.Array:
+		for idx := 0; idx < source.Len(); idx++ {
+			copyValue(target.Index(idx), source.Index(idx), visited)
+		}
+
+	case reflect.Map:
+		if source.IsNil() {
+			return
+		}
+		copied := reflect.MakeMapWithSize(source.Type(), source.Len())
+		iter := source.MapRange()
+		for iter.Next() {
+			key := reflect.New(iter.Key().Type()).Elem()
+			copyValue(key, iter.Key(), visited)
+			element := reflect.New(iter.Value().Type()).Elem()
+			copyValue(element, iter.Value(), visited)
+			copied.SetMapIndex(key, element)
+		}
+		target.Set(copied)
+
+	default:
+		target.Set(source)
+	}
+}
+
+// settable allows to read and write unexported struct fields
+func settable(field reflect.Value) reflect.Value {
+	if field.CanSet() {
+		return field
+	}
+	return reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem()
+}
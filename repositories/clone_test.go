@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type document struct {
+	Id      string
+	Tags    []string
+	Meta    map[string]*entity
+	private *entity
+}
+
+type cloneCounter struct {
+	Id     string
+	clones *int
+}
+
+func (c *cloneCounter) Clone() *cloneCounter {
+	*c.clones++
+	return &cloneCounter{c.Id, c.clones}
+}
+
+func Test_Isolate_protects_stored_entity_from_changes(t *testing.T) {
+	repo := newRepo()
+	repo.Isolate = true
+
+	toStore := &entity{Key1, 42}
+	_, _ = repo.Create(toStore)
+	toStore.Value = 1
+
+	found, _ := repo.FindByID(Key1)
+	found.Value = 2
+
+	again, _ := repo.FindByID(Key1)
+	assert.Equal(t, 42, again.Value)
+	assert.Equal(t, 42, repo.FindBy(func(*entity) bool { return true })[0].Value)
+}
+
+func Test_Isolate_uses_Clone_when_available(t *testing.T) {
+	clones := 0
+	repo := &InMemoryRepository[string, *cloneCounter]{
+		GetKey:  func(e *cloneCounter) string { return e.Id },
+		Isolate: true,
+	}
+
+	_, _ = repo.Create(&cloneCounter{Key1, &clones})
+	_, _ = repo.FindByID(Key1)
+
+	assert.Equal(t, 2, clones)
+}
+
+func Test_Isolate_watchers_receive_events_without_old_or_new_entity(t *testing.T) {
+	clones := 0
+	repo := &InMemoryRepository[string, *cloneCounter]{
+		GetKey:  func(e *cloneCounter) string { return e.Id },
+		Isolate: true,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := repo.Watch(ctx, nil)
+
+	_, err := repo.Create(&cloneCounter{Key1, &clones})
+	assert.Nil(t, err)
+	assert.Nil(t, repo.Delete(Key1))
+
+	created := <-events
+	assert.Equal(t, Created, created.Type)
+	assert.Nil(t, created.Old)
+	assert.Equal(t, Key1, created.New.Id)
+	deleted := <-events
+	assert.Equal(t, Deleted, deleted.Type)
+	assert.Equal(t, Key1, deleted.Old.Id)
+	assert.Nil(t, deleted.New)
+}
+
+func Test_deepCopy_copies_nested_values(t *testing.T) {
+	shared := &entity{"shared", 1}
+	original := &document{
+		Id:      Key1,
+		Tags:    []string{"a", "b"},
+		Meta:    map[string]*entity{"one": shared, "same": shared},
+		private: &entity{"private", 2},
+	}
+
+	copied := deepCopy(original)
+
+	assert.Equal(t, original, copied)
+	copied.Tags[0] = "changed"
+	copied.Meta["one"].Value = 100
+	copied.private.Value = 200
+	assert.Equal(t, "a", original.Tags[0])
+	assert.Equal(t, 1, shared.Value)
+	assert.Equal(t, 2, original.private.Value)
+	assert.Same(t, copied.Meta["one"], copied.Meta["same"])
+}
+
+func Test_deepCopy_copies_pointers_to_a_struct_and_its_first_field(t *testing.T) {
+	type inner struct{ Value int }
+	type outer struct {
+		Whole *inner
+		First *int
+	}
+	shared := &inner{42}
+	original := outer{Whole: shared, First: &shared.Value}
+
+	copied := deepCopy(original)
+
+	assert.Equal(t, original, copied)
+	assert.NotSame(t, original.Whole, copied.Whole)
+	assert.NotSame(t, original.First, copied.First)
+}
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/totemcaf/gollections/types"
+)
+
+// Repository is the context aware API of a repository. InMemoryRepository implements it, so it can be replaced
+// by a real backend in code that threads contexts through.
+type Repository[Key comparable, Entity any] interface {
+	CreateCtx(ctx context.Context, entity Entity) (Entity, error)
+	CreateAllCtx(ctx context.Context, entities ...Entity) ([]Entity, []error)
+	UpdateCtx(ctx context.Context, entity Entity) (Entity, error)
+	UpsertCtx(ctx context.Context, entity Entity) (Entity, error)
+	UpsertAllCtx(ctx context.Context, entities ...Entity) ([]Entity, []error)
+	DeleteCtx(ctx context.Context, key Key) error
+	DeleteByCtx(ctx context.Context, predicate types.Predicate[Entity]) (int, error)
+	FindByIDCtx(ctx context.Context, key Key) (Entity, error)
+	FindByCtx(ctx context.Context, predicate types.Predicate[Entity]) ([]Entity, error)
+	FindOneByCtx(ctx context.Context, predicate types.Predicate[Entity]) (Entity, error)
+}
+
+// lockCtx takes the write lock, giving up if ctx is done first
+func (r *InMemoryRepository[Key, Entity]) lockCtx(ctx context.Context) error {
+	return acquire(ctx, r.lock.Lock, r.lock.TryLock, r.lock.Unlock)
+}
+
+// rLockCtx takes the read lock, giving up if ctx is done first
+func (r *InMemoryRepository[Key, Entity]) rLockCtx(ctx context.Context) error {
+	return acquire(ctx, r.lock.RLock, r.lock.TryRLock, r.lock.RUnlock)
+}
+
+// acquire takes a lock. If ctx can be cancelled and the lock is not free, a goroutine waits for it in the lock
+// queue, so a waiting writer blocks new readers as with Lock, and hands it over unless ctx is done first. In that
+// case the goroutine releases the lock as soon as it gets it.
+func acquire(ctx context.Context, lock func(), tryLock func() bool, unlock func()) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if ctx.Done() == nil {
+		lock()
+		return nil
+	}
+
+	if tryLock() {
+		return nil
+	}
+
+	locked := make(chan struct{})
+	go func() {
+		lock()
+		select {
+		case locked <- struct{}{}:
+		case <-ctx.Done():
+			unlock()
+		}
+	}()
+
+	select {
+	case <-locked:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+var _ Repository[string, any] = &InMemoryRepository[string, any]{}
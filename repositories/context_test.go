@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tenantKey struct{}
+
+func Test_CreateCtx_fails_if_context_is_cancelled(t *testing.T) {
+	repo := newRepo()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := repo.CreateCtx(ctx, &entity{Key1, 42})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, repo.TotalCount())
+}
+
+func Test_FindByIDCtx_gives_up_waiting_for_the_lock(t *testing.T) {
+	repo := newRepo()
+	_, _ = repo.Create(&entity{Key1, 42})
+
+	repo.lock.Lock()
+	defer repo.lock.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := repo.FindByIDCtx(ctx, Key1)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func Test_UpdateCtx_is_not_starved_by_readers(t *testing.T) {
+	repo := newRepo()
+	_, _ = repo.Create(&entity{Key1, 42})
+
+	// The readers start staggered and overlap, so the read lock is never free for long
+	readers, stop := context.WithCancel(context.Background())
+	var started, done sync.WaitGroup
+	for reader := 0; reader < 16; reader++ {
+		started.Add(1)
+		done.Add(1)
+		go func(reader int) {
+			defer done.Done()
+			time.Sleep(time.Duration(reader) * 100 * time.Microsecond)
+			for first := true; readers.Err() == nil; first = false {
+				if repo.rLockCtx(readers) == nil {
+					if first {
+						started.Done()
+					}
+					time.Sleep(time.Millisecond)
+					repo.lock.RUnlock()
+				}
+			}
+		}(reader)
+	}
+	defer done.Wait()
+	defer stop()
+	started.Wait()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := repo.UpdateCtx(ctx, &entity{Key1, 4242})
+
+	assert.Nil(t, err)
+}
+
+func Test_latency_respects_context_deadline(t *testing.T) {
+	repo := newRepo()
+	repo.Faults = NewFaultInjector().WithLatency(time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := repo.FindByCtx(ctx, func(*entity) bool { return true })
+	_, errOne := repo.FindOneByCtx(ctx, func(*entity) bool { return true })
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorIs(t, errOne, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func Test_context_values_reach_fault_injector(t *testing.T) {
+	repo := newRepo()
+	repo.Faults = NewFaultInjector().FailWhen(func(call Call) bool {
+		return call.Context.Value(tenantKey{}) == "blocked"
+	}, unavailable)
+
+	_, errBlocked := repo.CreateCtx(context.WithValue(context.Background(), tenantKey{}, "blocked"), &entity{"a", 1})
+	_, errAllowed := repo.CreateCtx(context.WithValue(context.Background(), tenantKey{}, "allowed"), &entity{"b", 2})
+
+	assert.ErrorIs(t, errBlocked, unavailable)
+	assert.Nil(t, errAllowed)
+}
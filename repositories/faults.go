@@ -0,0 +1,161 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/totemcaf/gollections/syncs"
+	"github.com/totemcaf/gollections/types"
+)
+
+var injectedFault = errors.New("injected fault")
+
+// Operation names a repository method for fault injection
+type Operation string
+
+const (
+	OpCreate       Operation = "Create"
+	OpCreateAll    Operation = "CreateAll"
+	OpUpdate       Operation = "Update"
+	OpUpsert       Operation = "Upsert"
+	OpUpsertAll    Operation = "UpsertAll"
+	OpDelete       Operation = "Delete"
+	OpDeleteBy     Operation = "DeleteBy"
+	OpFindByID     Operation = "FindByID"
+	OpFindBy       Operation = "FindBy"
+	OpFindOneBy    Operation = "FindOneBy"
+	OpRestore      Operation = "Restore"
+	OpPurge        Operation = "Purge"
+	OpPurgeDeleted Operation = "PurgeDeleted"
+)
+
+// Call describes a repository call seen by the FaultInjector
+type Call struct {
+	Operation Operation
+	// Number is the position of the call, starting at 1, among all the calls seen by the injector
+	Number int
+	// Key is the key received by the call, if any
+	Key any
+	// Context is the context received by the call, it allows to select calls by values such as a tenant
+	Context context.Context
+}
+
+type faultRule struct {
+	matches types.Predicate[Call]
+	err     error
+}
+
+// FaultInjector makes a repository fail or slow down on demand. Assign it to InMemoryRepository.Faults.
+// Only the methods that can return an error are affected.
+type FaultInjector struct {
+	lock       sync.Mutex
+	calls      int
+	rules      []faultRule
+	minLatency time.Duration
+	maxLatency time.Duration
+	random     *rand.Rand
+}
+
+// NewFaultInjector creates an injector that does not fail nor delay any call
+func NewFaultInjector() *FaultInjector {
+	return &FaultInjector{}
+}
+
+// FailNth makes the nth call (starting at 1) fail with err. If err is nil, a generic error is used.
+func (f *FaultInjector) FailNth(n int, err error) *FaultInjector {
+	return f.FailWhen(func(call Call) bool { return call.Number == n }, err)
+}
+
+// FailOperation makes all the calls to the operation fail with err. If err is nil, a generic error is used.
+func (f *FaultInjector) FailOperation(operation Operation, err error) *FaultInjector {
+	return f.FailWhen(func(call Call) bool { return call.Operation == operation }, err)
+}
+
+// FailWhen makes the calls that satisfy predicate fail with err. If err is nil, a generic error is used.
+func (f *FaultInjector) FailWhen(predicate types.Predicate[Call], err error) *FaultInjector {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if err == nil {
+		err = injectedFault
+	}
+	f.rules = append(f.rules, faultRule{predicate, err})
+
+	return f
+}
+
+// WithLatency delays all the calls by the given duration
+func (f *FaultInjector) WithLatency(latency time.Duration) *FaultInjector {
+	return f.WithRandomLatency(latency, latency)
+}
+
+// WithRandomLatency delays all the calls by a random duration between min and max
+func (f *FaultInjector) WithRandomLatency(min, max time.Duration) *FaultInjector {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.minLatency, f.maxLatency = min, max
+
+	return f
+}
+
+// Reset removes all the failures and latency and restarts the call count
+func (f *FaultInjector) Reset() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.calls = 0
+	f.rules = nil
+	f.minLatency, f.maxLatency = 0, 0
+}
+
+// Calls returns the number of calls seen
+func (f *FaultInjector) Calls() int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.calls
+}
+
+// inject delays the call and returns the error it must fail with, if any.
+// If ctx is done while waiting, its error is returned.
+func (f *FaultInjector) inject(ctx context.Context, operation Operation, key any) error {
+	if f == nil {
+		return nil
+	}
+
+	latency, err := f.register(Call{Operation: operation, Key: key, Context: ctx})
+
+	if latency > 0 && syncs.Sleep(ctx, latency) {
+		return ctx.Err()
+	}
+
+	return err
+}
+
+func (f *FaultInjector) register(call Call) (time.Duration, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.calls++
+	call.Number = f.calls
+
+	latency := f.minLatency
+	if f.maxLatency > f.minLatency {
+		if f.random == nil {
+			f.random = rand.New(rand.NewSource(time.Now().UnixNano()))
+		}
+		latency += time.Duration(f.random.Int63n(int64(f.maxLatency - f.minLatency)))
+	}
+
+	for _, rule := range f.rules {
+		if rule.matches(call) {
+			return latency, rule.err
+		}
+	}
+
+	return latency, nil
+}
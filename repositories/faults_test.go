@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var unavailable = errors.New("unavailable")
+
+func Test_FailNth_fails_only_that_call(t *testing.T) {
+	repo := newRepo()
+	repo.Faults = NewFaultInjector().FailNth(2, unavailable)
+
+	_, err1 := repo.Create(&entity{"a-key-001", 1})
+	_, err2 := repo.Create(&entity{"a-key-002", 2})
+	_, err3 := repo.Create(&entity{"a-key-003", 3})
+
+	assert.Nil(t, err1)
+	assert.ErrorIs(t, err2, unavailable)
+	assert.Nil(t, err3)
+	assert.Equal(t, 2, repo.TotalCount())
+	assert.Equal(t, 3, repo.Faults.Calls())
+}
+
+func Test_FailWhen_fails_matching_calls(t *testing.T) {
+	repo := newRepo()
+	_, _ = repo.Create(&entity{Key1, 42})
+	repo.Faults = NewFaultInjector().FailWhen(func(call Call) bool {
+		return call.Operation == OpFindByID && call.Key == Key1
+	}, nil)
+
+	_, errFind := repo.FindByID(Key1)
+	errDelete := repo.Delete(Key1)
+
+	assert.ErrorIs(t, errFind, injectedFault)
+	assert.Nil(t, errDelete)
+}
+
+func Test_FailOperation_fails_all_batch_items(t *testing.T) {
+	repo := newRepo()
+	repo.Faults = NewFaultInjector().FailOperation(OpCreateAll, unavailable)
+
+	_, errs := repo.CreateAll(&entity{"a-key-001", 1}, &entity{"a-key-002", 2})
+
+	assert.Equal(t, []error{unavailable, unavailable}, errs)
+	assert.Equal(t, 0, repo.TotalCount())
+}
+
+func Test_FailOperation_fails_FindBy_and_DeleteBy(t *testing.T) {
+	repo := newRepo()
+	_, _ = repo.Create(&entity{Key1, 42})
+	repo.Faults = NewFaultInjector().FailOperation(OpFindBy, unavailable).FailOperation(OpDeleteBy, unavailable)
+
+	_, errFind := repo.FindByCtx(context.Background(), func(*entity) bool { return true })
+	count, errDelete := repo.DeleteByCtx(context.Background(), func(*entity) bool { return true })
+
+	assert.ErrorIs(t, errFind, unavailable)
+	assert.ErrorIs(t, errDelete, unavailable)
+	assert.Equal(t, 0, count)
+	assert.Equal(t, 1, repo.TotalCount())
+}
+
+func Test_FindBy_and_DeleteBy_hide_injected_faults(t *testing.T) {
+	repo := newRepo()
+	_, _ = repo.Create(&entity{Key1, 42})
+	repo.Faults = NewFaultInjector().FailOperation(OpFindBy, unavailable).FailOperation(OpDeleteBy, unavailable)
+
+	found := repo.FindBy(func(*entity) bool { return true })
+	count := repo.DeleteBy(func(*entity) bool { return true })
+
+	assert.Empty(t, found)
+	assert.Equal(t, 0, count)
+	assert.Equal(t, 1, repo.TotalCount())
+}
+
+func Test_FindOneBy_is_a_single_call(t *testing.T) {
+	repo := newRepo()
+	_, _ = repo.Create(&entity{Key1, 42})
+	repo.Faults = NewFaultInjector().FailOperation(OpFindBy, unavailable)
+
+	found, err := repo.FindOneBy(func(*entity) bool { return true })
+
+	assert.Nil(t, err)
+	assert.Equal(t, 42, found.Value)
+	assert.Equal(t, 1, repo.Faults.Calls())
+}
+
+func Test_WithLatency_delays_calls(t *testing.T) {
+	repo := newRepo()
+	repo.Faults = NewFaultInjector().WithLatency(20 * time.Millisecond)
+
+	start := time.Now()
+	_, err := repo.Create(&entity{Key1, 42})
+
+	assert.Nil(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+}
+
+func Test_Reset_removes_faults(t *testing.T) {
+	repo := newRepo()
+	repo.Faults = NewFaultInjector().FailOperation(OpCreate, unavailable)
+
+	repo.Faults.Reset()
+	_, err := repo.Create(&entity{Key1, 42})
+
+	assert.Nil(t, err)
+}
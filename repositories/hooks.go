@@ -0,0 +1,92 @@
+package repositories
+
+// Hooks run while the write lock is held, so they must not call the repository. A hook that returns an error
+// aborts the operation and the error is returned to the caller.
+
+// prepareCreate runs the validation and BeforeCreate. The entity is stamped only once they pass, so a rejected
+// entity is not changed.
+func (r *InMemoryRepository[Key, Entity]) prepareCreate(entity Entity) error {
+	if err := r.validate(entity); err != nil {
+		return err
+	}
+	if r.BeforeCreate != nil {
+		return r.BeforeCreate(entity)
+	}
+	return nil
+}
+
+// afterCreate runs AfterCreate on an entity that is already stored
+func (r *InMemoryRepository[Key, Entity]) afterCreate(entity Entity) error {
+	if r.AfterCreate == nil {
+		return nil
+	}
+	return r.AfterCreate(entity)
+}
+
+// undoCreated removes entities that were already stored by a creation that failed afterwards. Watchers receive a
+// Deleted event for each of them. It must be called with the write lock held.
+func (r *InMemoryRepository[Key, Entity]) undoCreated(keys []Key) {
+	for _, key := range keys {
+		entity := r.elementsById[key]
+		delete(r.elementsById, key)
+		r.publish(Event[Key, Entity]{Type: Deleted, Key: key, Old: entity})
+	}
+
+	// The created records are already in the log, rewriting it drops them. If that fails the log is rewritten
+	// again by the next compaction.
+	if r.log != nil {
+		_ = r.compactLog()
+	}
+}
+
+// create adds a new entity with the given key that is not in use. It must be called with the write lock held.
+func (r *InMemoryRepository[Key, Entity]) create(key Key, entity Entity) (Entity, error) {
+	if err := r.prepareCreate(entity); err != nil {
+		return entity, err
+	}
+	entity = r.stampCreated(entity)
+
+	if err := r.apply(Event[Key, Entity]{Type: Created, Key: key, New: entity}); err != nil {
+		return entity, err
+	}
+
+	if err := r.afterCreate(entity); err != nil {
+		r.undoCreated([]Key{key})
+		return entity, err
+	}
+
+	return entity, nil
+}
+
+// update replaces the old entity with the given key. It must be called with the write lock held.
+func (r *InMemoryRepository[Key, Entity]) update(key Key, old Entity, entity Entity) (Entity, error) {
+	if err := r.validate(entity); err != nil {
+		return entity, err
+	}
+	if r.BeforeUpdate != nil {
+		if err := r.BeforeUpdate(r.clone(old), entity); err != nil {
+			return entity, err
+		}
+	}
+	entity = r.stampUpdated(entity)
+
+	return entity, r.apply(Event[Key, Entity]{Type: Updated, Key: key, Old: old, New: entity})
+}
+
+// remove deletes the entity with the given key. It must be called with the write lock held.
+func (r *InMemoryRepository[Key, Entity]) remove(key Key, entity Entity) error {
+	if r.BeforeDelete != nil {
+		if err := r.BeforeDelete(r.clone(entity)); err != nil {
+			return err
+		}
+	}
+
+	return r.apply(r.deletion(key, entity))
+}
+
+func (r *InMemoryRepository[Key, Entity]) validate(entity Entity) error {
+	if r.Validate == nil {
+		return nil
+	}
+	return r.Validate(entity)
+}
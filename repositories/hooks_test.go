@@ -0,0 +1,163 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/totemcaf/gollections/strs"
+)
+
+var invalidName = errors.New("invalid name")
+
+func newValidatedRepo() *InMemoryRepository[string, *entity] {
+	repo := newRepo()
+	repo.Validate = func(e *entity) error {
+		if strs.IsNotProperName(e.Id) {
+			return invalidName
+		}
+		return nil
+	}
+	return repo
+}
+
+func Test_Validate_rejects_invalid_entities(t *testing.T) {
+	repo := newValidatedRepo()
+	_, _ = repo.Create(&entity{Key1, 42})
+
+	_, errCreate := repo.Create(&entity{" padded", 42})
+	_, errUpsert := repo.Upsert(&entity{"with\ttab", 42})
+
+	assert.ErrorIs(t, errCreate, invalidName)
+	assert.ErrorIs(t, errUpsert, invalidName)
+	assert.Equal(t, 1, repo.TotalCount())
+}
+
+func Test_CreateAll_adds_nothing_if_hook_fails(t *testing.T) {
+	repo := newValidatedRepo()
+
+	_, errs := repo.CreateAll(&entity{"a-key-001", 1}, &entity{"a-key-002 ", 2})
+
+	assert.Equal(t, []error{batchAborted, invalidName}, errs)
+	assert.Equal(t, 0, repo.TotalCount())
+}
+
+func Test_AfterCreate_error_aborts_creation(t *testing.T) {
+	repo := newRepo()
+	repo.AfterCreate = func(e *entity) error {
+		if e.Value < 0 {
+			return errors.New("negative")
+		}
+		return nil
+	}
+
+	_, err := repo.Create(&entity{Key1, -1})
+
+	assert.EqualError(t, err, "negative")
+	assert.Equal(t, 0, repo.TotalCount())
+}
+
+func Test_AfterCreate_runs_after_the_entity_is_stored(t *testing.T) {
+	repo := newRepo()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := repo.Watch(ctx, nil)
+	repo.AfterCreate = func(e *entity) error {
+		assert.Equal(t, Created, (<-events).Type)
+		return errors.New("rejected")
+	}
+
+	_, err := repo.Create(&entity{Key1, 42})
+
+	assert.EqualError(t, err, "rejected")
+	assert.Equal(t, Event[string, *entity]{Type: Deleted, Key: Key1, Old: &entity{Key1, 42}}, <-events)
+	assert.Equal(t, 0, repo.TotalCount())
+}
+
+func Test_CreateAll_removes_all_entities_if_AfterCreate_fails(t *testing.T) {
+	repo := newRepo()
+	repo.AfterCreate = func(e *entity) error {
+		if e.Value < 0 {
+			return errors.New("negative")
+		}
+		return nil
+	}
+
+	_, errs := repo.CreateAll(&entity{"a-key-001", 1}, &entity{"a-key-002", -2}, &entity{"a-key-003", 3})
+
+	assert.Equal(t, batchAborted, errs[0])
+	assert.EqualError(t, errs[1], "negative")
+	assert.Equal(t, batchAborted, errs[2])
+	assert.Equal(t, 0, repo.TotalCount())
+}
+
+func Test_BeforeUpdate_receives_old_and_new_entity(t *testing.T) {
+	repo := newRepo()
+	repo.BeforeUpdate = func(old *entity, new *entity) error {
+		if new.Value < old.Value {
+			return errors.New("cannot decrease")
+		}
+		return nil
+	}
+	_, _ = repo.Create(&entity{Key1, 42})
+
+	_, errDecrease := repo.Update(&entity{Key1, 41})
+	_, errIncrease := repo.Update(&entity{Key1, 43})
+
+	assert.EqualError(t, errDecrease, "cannot decrease")
+	assert.Nil(t, errIncrease)
+}
+
+func Test_BeforeDelete_error_keeps_entity(t *testing.T) {
+	repo := newRepo()
+	repo.BeforeDelete = func(e *entity) error {
+		if e.Value > 100 {
+			return errors.New("protected")
+		}
+		return nil
+	}
+	_, _ = repo.CreateAll(&entity{"a-key-001", 4200}, &entity{"a-key-002", 42})
+
+	err := repo.Delete("a-key-001")
+	count := repo.DeleteAll()
+
+	assert.EqualError(t, err, "protected")
+	assert.Equal(t, 1, count)
+	assert.Equal(t, 1, repo.TotalCount())
+}
+
+func Test_rejected_Create_does_not_stamp_the_entity(t *testing.T) {
+	repo := &InMemoryRepository[string, *audited]{
+		GetKey: func(e *audited) string { return e.Id },
+		Clock:  func() time.Time { return now },
+		SetCreatedAt: func(e *audited, t time.Time) *audited {
+			e.CreatedAt = t
+			return e
+		},
+		Validate: func(*audited) error { return invalidName },
+	}
+	toCreate := &audited{Id: Key1}
+
+	_, err := repo.Create(toCreate)
+
+	assert.ErrorIs(t, err, invalidName)
+	assert.True(t, toCreate.CreatedAt.IsZero())
+}
+
+func Test_BeforeDelete_cannot_change_isolated_entity(t *testing.T) {
+	repo := newRepo()
+	repo.Isolate = true
+	repo.BeforeDelete = func(e *entity) error {
+		e.Value = 0
+		return errors.New("protected")
+	}
+	_, _ = repo.Create(&entity{Key1, 42})
+
+	err := repo.Delete(Key1)
+
+	assert.EqualError(t, err, "protected")
+	found, _ := repo.FindByID(Key1)
+	assert.Equal(t, 42, found.Value)
+}
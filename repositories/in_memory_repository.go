@@ -1,10 +1,13 @@
 package repositories
 
 import (
+	"context"
 	"errors"
 	"sync"
+	"time"
 
 	"github.com/totemcaf/gollections/maps"
+	"github.com/totemcaf/gollections/sets"
 	"github.com/totemcaf/gollections/slices"
 	"github.com/totemcaf/gollections/types"
 )
@@ -16,39 +19,103 @@ var duplicateKey = errors.New("key is duplicated")
 
 type InMemoryRepository[Key comparable, Entity any] struct {
 	elementsById  map[Key]Entity
+	deletedById   map[Key]Entity
 	emptyKey      Key
 	lock          sync.RWMutex
+	watchers      sets.Set[*watcher[Key, Entity]]
+	log           *writeAheadLog
 	AllowEmptyKey bool
 	GetKey        func(Entity) Key
+	// GenerateKey, if set together with SetKey, is used by Create to assign a key to entities without one
+	GenerateKey func() Key
+	// SetKey returns the entity with the given key
+	SetKey func(Entity, Key) Entity
+	// Codec is used to save and load snapshots and the write-ahead log. Defaults to JSONCodec
+	Codec Codec
+	// SoftDelete makes Delete keep the entities apart, hidden from queries, so they can be restored or purged
+	SoftDelete bool
+	// Clock is used to stamp entities. Defaults to time.Now
+	Clock func() time.Time
+	// SetCreatedAt, if set, is used to stamp the creation time of entities
+	SetCreatedAt func(Entity, time.Time) Entity
+	// SetUpdatedAt, if set, is used to stamp the creation and update time of entities
+	SetUpdatedAt func(Entity, time.Time) Entity
+	// SetDeletedAt, if set, is used to stamp the time entities are soft deleted, or to clear it when restored
+	SetDeletedAt func(Entity, time.Time) Entity
+	// Validate, if set, checks entities before they are created or updated. Entities are stamped with
+	// SetCreatedAt and SetUpdatedAt only after Validate and the before hooks pass
+	Validate func(Entity) error
+	// BeforeCreate, if set, is called before an entity is created, before AfterCreate
+	BeforeCreate func(Entity) error
+	// AfterCreate, if set, is called with the entity after it is stored. If it returns an error the entity is
+	// removed again, watchers receive a Deleted event for it and the error is returned
+	AfterCreate func(Entity) error
+	// BeforeUpdate, if set, is called with the stored and the new entity before an update. With Isolate the stored
+	// entity is a copy
+	BeforeUpdate func(old Entity, new Entity) error
+	// BeforeDelete, if set, is called before an entity is deleted. With Isolate it receives a copy
+	BeforeDelete func(Entity) error
+	// Isolate makes the repository store and return copies of the entities, so changing an entity outside the
+	// repository does not change the stored one. Entities implementing types.Cloneable are copied with Clone.
+	Isolate bool
+	// Faults, if set, makes calls fail or delay on demand
+	Faults *FaultInjector
 }
 
 func (r *InMemoryRepository[Key, Entity]) init() {
 	if r.elementsById == nil {
 		r.elementsById = make(map[Key]Entity, 16)
 	}
+	if r.deletedById == nil {
+		r.deletedById = make(map[Key]Entity)
+	}
 }
 
+// Create adds the entity. If it has no key and the repository has a key generator, a new key is assigned.
 func (r *InMemoryRepository[Key, Entity]) Create(entity Entity) (Entity, error) {
-	r.lock.Lock()
+	return r.CreateCtx(context.Background(), entity)
+}
+
+// CreateCtx is like Create but gives up if ctx is done before the operation starts
+func (r *InMemoryRepository[Key, Entity]) CreateCtx(ctx context.Context, entity Entity) (Entity, error) {
+	if err := r.Faults.inject(ctx, OpCreate, r.GetKey(entity)); err != nil {
+		return entity, err
+	}
+
+	if err := r.lockCtx(ctx); err != nil {
+		return entity, err
+	}
 	defer r.lock.Unlock()
 	r.init()
 
-	key := r.GetKey(entity)
+	entity, key, err := r.assignKey(entity, nil)
+	if err != nil {
+		return entity, err
+	}
 	if !r.AllowEmptyKey && key == r.emptyKey {
 		return entity, invalidKey
 	}
 
-	_, alreadyInMap := r.elementsById[key]
-	if alreadyInMap {
+	if r.keyInUse(key) {
 		return entity, duplicateKey
 	}
 
-	r.elementsById[key] = entity
-	return entity, nil
+	return r.create(key, entity)
 }
 
 func (r *InMemoryRepository[Key, Entity]) Update(entity Entity) (Entity, error) {
-	r.lock.Lock()
+	return r.UpdateCtx(context.Background(), entity)
+}
+
+// UpdateCtx is like Update but gives up if ctx is done before the operation starts
+func (r *InMemoryRepository[Key, Entity]) UpdateCtx(ctx context.Context, entity Entity) (Entity, error) {
+	if err := r.Faults.inject(ctx, OpUpdate, r.GetKey(entity)); err != nil {
+		return entity, err
+	}
+
+	if err := r.lockCtx(ctx); err != nil {
+		return entity, err
+	}
 	defer r.lock.Unlock()
 	r.init()
 
@@ -57,17 +124,27 @@ func (r *InMemoryRepository[Key, Entity]) Update(entity Entity) (Entity, error)
 		return entity, invalidKey
 	}
 
-	_, alreadyInMap := r.elementsById[key]
+	old, alreadyInMap := r.elementsById[key]
 	if !alreadyInMap {
 		return entity, notFound
 	}
 
-	r.elementsById[key] = entity
-	return entity, nil
+	return r.update(key, old, entity)
 }
 
 func (r *InMemoryRepository[Key, Entity]) Delete(key Key) error {
-	r.lock.Lock()
+	return r.DeleteCtx(context.Background(), key)
+}
+
+// DeleteCtx is like Delete but gives up if ctx is done before the operation starts
+func (r *InMemoryRepository[Key, Entity]) DeleteCtx(ctx context.Context, key Key) error {
+	if err := r.Faults.inject(ctx, OpDelete, key); err != nil {
+		return err
+	}
+
+	if err := r.lockCtx(ctx); err != nil {
+		return err
+	}
 	defer r.lock.Unlock()
 	r.init()
 
@@ -75,49 +152,118 @@ func (r *InMemoryRepository[Key, Entity]) Delete(key Key) error {
 		return invalidKey
 	}
 
-	_, alreadyInMap := r.elementsById[key]
+	old, alreadyInMap := r.elementsById[key]
 
 	if !alreadyInMap {
 		return notFound
 	}
 
-	delete(r.elementsById, key)
+	return r.remove(key, old)
+}
+
+// apply commits a change: it is written to the log (if any), applied to the elements and published to watchers.
+// It must be called with the write lock held.
+func (r *InMemoryRepository[Key, Entity]) apply(event Event[Key, Entity]) error {
+	if err := r.appendLog(event); err != nil {
+		return err
+	}
+
+	switch event.Type {
+	case Deleted:
+		delete(r.elementsById, event.Key)
+		if r.SoftDelete {
+			r.deletedById[event.Key] = r.clone(event.New)
+		}
+	case Restored:
+		delete(r.deletedById, event.Key)
+		r.elementsById[event.Key] = r.clone(event.New)
+	case Purged:
+		delete(r.deletedById, event.Key)
+	default:
+		r.elementsById[event.Key] = r.clone(event.New)
+	}
+
+	r.publish(event)
+	r.compactLogIfNeeded()
 
 	return nil
 }
 
 func (r *InMemoryRepository[Key, Entity]) FindByID(key Key) (Entity, error) {
-	r.lock.RLock()
+	return r.FindByIDCtx(context.Background(), key)
+}
+
+// FindByIDCtx is like FindByID but gives up if ctx is done before the operation starts
+func (r *InMemoryRepository[Key, Entity]) FindByIDCtx(ctx context.Context, key Key) (Entity, error) {
+	var empty Entity
+
+	if err := r.Faults.inject(ctx, OpFindByID, key); err != nil {
+		return empty, err
+	}
+
+	if err := r.rLockCtx(ctx); err != nil {
+		return empty, err
+	}
 	defer r.lock.RUnlock()
 	r.init()
 
 	if !r.AllowEmptyKey && key == r.emptyKey {
-		var entity Entity
-		return entity, invalidKey
+		return empty, invalidKey
 	}
 
 	if entity, found := r.elementsById[key]; found {
-		return entity, nil
+		return r.clone(entity), nil
 	}
-	var empty Entity
 	return empty, notFound
 }
 
+// FindBy returns the entities that satisfy predicate. It cannot report errors: a fault injected by Faults gives
+// an empty result. Use FindByCtx to get the error.
 func (r *InMemoryRepository[Key, Entity]) FindBy(predicate types.Predicate[Entity]) []Entity {
-	r.lock.RLock()
+	found, _ := r.FindByCtx(context.Background(), predicate)
+	return found
+}
+
+// FindByCtx is like FindBy but gives up if ctx is done before the operation starts
+func (r *InMemoryRepository[Key, Entity]) FindByCtx(ctx context.Context, predicate types.Predicate[Entity]) ([]Entity, error) {
+	if err := r.Faults.inject(ctx, OpFindBy, nil); err != nil {
+		return nil, err
+	}
+
+	return r.find(ctx, predicate)
+}
+
+// find returns the entities that satisfy predicate, without injecting faults
+func (r *InMemoryRepository[Key, Entity]) find(ctx context.Context, predicate types.Predicate[Entity]) ([]Entity, error) {
+	if err := r.rLockCtx(ctx); err != nil {
+		return nil, err
+	}
 	defer r.lock.RUnlock()
 
 	// This is not the most efficient way to do it, but this repository is meant for tests
-	return slices.Filter(maps.Values(r.elementsById), predicate)
+	return r.cloneAll(slices.Filter(maps.Values(r.elementsById), predicate)), nil
 }
 
 // FindOneBy returns the first element that satisfies the predicate. If more than one or none found, returns an error.
 func (r *InMemoryRepository[Key, Entity]) FindOneBy(predicate types.Predicate[Entity]) (Entity, error) {
-	found := r.FindBy(predicate)
+	return r.FindOneByCtx(context.Background(), predicate)
+}
+
+// FindOneByCtx is like FindOneBy but gives up if ctx is done before the operation starts
+func (r *InMemoryRepository[Key, Entity]) FindOneByCtx(ctx context.Context, predicate types.Predicate[Entity]) (Entity, error) {
+	var empty Entity
+
+	if err := r.Faults.inject(ctx, OpFindOneBy, nil); err != nil {
+		return empty, err
+	}
+
+	found, err := r.find(ctx, predicate)
+	if err != nil {
+		return empty, err
+	}
 
 	switch len(found) {
 	case 0:
-		var empty Entity
 		return empty, notFound
 	case 1:
 		return found[0], nil
@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"sync"
+
+	"github.com/totemcaf/gollections/strs"
+	"golang.org/x/exp/constraints"
+)
+
+// maxKeyAttempts limits how many keys are generated looking for one not in use
+const maxKeyAttempts = 100
+
+// SequentialKeys returns a key generator that produces 1, 2, 3, ...
+func SequentialKeys[K constraints.Integer]() func() K {
+	var lock sync.Mutex
+	var last K
+
+	return func() K {
+		lock.Lock()
+		defer lock.Unlock()
+
+		last++
+		return last
+	}
+}
+
+// RandomStringKeys returns a key generator that produces random strings of the given size. Generators can be
+// used concurrently.
+func RandomStringKeys(size int) func() string {
+	return func() string {
+		return strs.RandString(size)
+	}
+}
+
+// UUIDKeys returns a key generator that produces random UUID strings
+func UUIDKeys() func() string {
+	return strs.RandUUID
+}
+
+// canGenerateKey returns true if the repository is configured to assign keys
+func (r *InMemoryRepository[Key, Entity]) canGenerateKey() bool {
+	return r.GenerateKey != nil && r.SetKey != nil
+}
+
+// assignKey returns the entity and its key. If the entity has no key and the repository can generate keys,
+// a fresh key not in use (neither in the repository nor in taken) is assigned. It must be called with the write
+// lock held.
+func (r *InMemoryRepository[Key, Entity]) assignKey(entity Entity, taken map[Key]struct{}) (Entity, Key, error) {
+	key := r.GetKey(entity)
+
+	if key != r.emptyKey || !r.canGenerateKey() {
+		return entity, key, nil
+	}
+
+	for attempt := 0; attempt < maxKeyAttempts; attempt++ {
+		key = r.GenerateKey()
+
+		if key == r.emptyKey {
+			continue
+		}
+		if r.keyInUse(key) {
+			continue
+		}
+		if _, found := taken[key]; found {
+			continue
+		}
+
+		return r.SetKey(entity, key), key, nil
+	}
+
+	return entity, key, duplicateKey
+}
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type numbered struct {
+	Id   int
+	Name string
+}
+
+func newNumberedRepo() *InMemoryRepository[int, numbered] {
+	return &InMemoryRepository[int, numbered]{
+		GetKey:      func(e numbered) int { return e.Id },
+		GenerateKey: SequentialKeys[int](),
+		SetKey: func(e numbered, id int) numbered {
+			e.Id = id
+			return e
+		},
+	}
+}
+
+func Test_Create_assigns_key_to_entity_without_key(t *testing.T) {
+	repo := newNumberedRepo()
+
+	first, err1 := repo.Create(numbered{Name: "first"})
+	second, err2 := repo.Create(numbered{Name: "second"})
+
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.Equal(t, 1, first.Id)
+	assert.Equal(t, 2, second.Id)
+
+	found, err := repo.FindByID(2)
+	assert.Nil(t, err)
+	assert.Equal(t, "second", found.Name)
+}
+
+func Test_Create_skips_generated_keys_already_in_use(t *testing.T) {
+	repo := newNumberedRepo()
+	_, _ = repo.Create(numbered{Id: 1, Name: "explicit"})
+
+	created, err := repo.Create(numbered{Name: "generated"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, created.Id)
+}
+
+func Test_CreateAll_assigns_different_keys(t *testing.T) {
+	repo := newNumberedRepo()
+
+	created, errs := repo.CreateAll(numbered{Name: "a"}, numbered{Name: "b"})
+
+	assert.Equal(t, []error{nil, nil}, errs)
+	assert.Equal(t, []numbered{{1, "a"}, {2, "b"}}, created)
+}
+
+func Test_UUIDKeys_generates_different_keys(t *testing.T) {
+	repo := newRepo()
+	repo.GenerateKey = UUIDKeys()
+	repo.SetKey = func(e *entity, id string) *entity {
+		e.Id = id
+		return e
+	}
+
+	first, _ := repo.Create(&entity{Value: 1})
+	second, _ := repo.Create(&entity{Value: 2})
+
+	assert.Len(t, first.Id, 36)
+	assert.NotEqual(t, first.Id, second.Id)
+}
@@ -0,0 +1,391 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/totemcaf/gollections/slices"
+	"github.com/totemcaf/gollections/types"
+)
+
+var noPartition = errors.New("no partition in context")
+var mixedPartitions = errors.New("entities belong to different partitions")
+var wrongPartition = errors.New("entity does not belong to the partition in context")
+var droppedPartition = errors.New("partition dropped during the write")
+
+type partitionKey[Partition comparable] struct{}
+
+// WithPartition returns a context that scopes PartitionedRepository calls to the given partition
+func WithPartition[Partition comparable](ctx context.Context, partition Partition) context.Context {
+	return context.WithValue(ctx, partitionKey[Partition]{}, partition)
+}
+
+// PartitionFrom returns the partition set with WithPartition
+func PartitionFrom[Partition comparable](ctx context.Context) (Partition, bool) {
+	partition, ok := ctx.Value(partitionKey[Partition]{}).(Partition)
+	return partition, ok
+}
+
+// PartitionedRepository keeps entities in isolated partitions (for example, one per tenant). Each partition is an
+// InMemoryRepository with its own keyspace, lock and count.
+// Entities are stored in the partition getPartition returns for them. Calls that receive a key or a predicate are
+// scoped to the partition set in the context with WithPartition. Calls that receive entities fail if the context
+// has a partition and the entities belong to another one. A partition is only created when a write stores an
+// entity in it.
+type PartitionedRepository[Partition comparable, Key comparable, Entity any] struct {
+	lock         sync.RWMutex
+	partitions   map[Partition]*partitionEntry[Key, Entity]
+	getPartition func(Entity) Partition
+	newPartition func() *InMemoryRepository[Key, Entity]
+}
+
+type partitionEntry[Key comparable, Entity any] struct {
+	repo *InMemoryRepository[Key, Entity]
+	// writers is the number of writes in progress on the partition
+	writers int
+	// stored is false while the partition is new and no write has stored an entity in it yet
+	stored bool
+	// dropped is set when the partition is dropped while writes are in progress
+	dropped bool
+}
+
+// NewPartitionedRepository creates a repository that stores each entity in the partition getPartition returns for
+// it, creating the repository of a new partition with newPartition. Both functions are required.
+func NewPartitionedRepository[Partition comparable, Key comparable, Entity any](
+	getPartition func(Entity) Partition,
+	newPartition func() *InMemoryRepository[Key, Entity],
+) *PartitionedRepository[Partition, Key, Entity] {
+	if getPartition == nil || newPartition == nil {
+		panic("getPartition and newPartition are required")
+	}
+
+	return &PartitionedRepository[Partition, Key, Entity]{
+		partitions:   make(map[Partition]*partitionEntry[Key, Entity]),
+		getPartition: getPartition,
+		newPartition: newPartition,
+	}
+}
+
+// Partition returns the repository of the partition, or false if no entity was stored in it
+func (r *PartitionedRepository[Partition, Key, Entity]) Partition(partition Partition) (*InMemoryRepository[Key, Entity], bool) {
+	return r.find(partition)
+}
+
+// Partitions returns the existing partitions
+func (r *PartitionedRepository[Partition, Key, Entity]) Partitions() []Partition {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	partitions := make([]Partition, 0, len(r.partitions))
+	for partition, entry := range r.partitions {
+		if entry.stored {
+			partitions = append(partitions, partition)
+		}
+	}
+	return partitions
+}
+
+// DropPartition removes a partition with all its entities. Returns false if the partition does not exist.
+// Writes in progress on the partition fail with droppedPartition.
+func (r *PartitionedRepository[Partition, Key, Entity]) DropPartition(partition Partition) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	entry, found := r.partitions[partition]
+	if !found {
+		return false
+	}
+
+	entry.dropped = true
+	delete(r.partitions, partition)
+
+	return entry.stored
+}
+
+// TotalCount returns the number of entities in all the partitions
+func (r *PartitionedRepository[Partition, Key, Entity]) TotalCount() int {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	total := 0
+	for _, entry := range r.partitions {
+		if entry.stored {
+			total += entry.repo.TotalCount()
+		}
+	}
+	return total
+}
+
+func (r *PartitionedRepository[Partition, Key, Entity]) find(partition Partition) (*InMemoryRepository[Key, Entity], bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	entry, found := r.partitions[partition]
+	if !found || !entry.stored {
+		return nil, false
+	}
+	return entry.repo, true
+}
+
+// fromContext returns the repository of the partition in the context, if it exists
+func (r *PartitionedRepository[Partition, Key, Entity]) fromContext(ctx context.Context) (*InMemoryRepository[Key, Entity], bool, error) {
+	partition, ok := PartitionFrom[Partition](ctx)
+	if !ok {
+		return nil, false, noPartition
+	}
+
+	repo, found := r.find(partition)
+	return repo, found, nil
+}
+
+// partitionOf returns the partition of the entity. It fails if the context is scoped to another partition.
+func (r *PartitionedRepository[Partition, Key, Entity]) partitionOf(ctx context.Context, entity Entity) (Partition, error) {
+	partition := r.getPartition(entity)
+
+	if scoped, ok := PartitionFrom[Partition](ctx); ok && scoped != partition {
+		return partition, wrongPartition
+	}
+
+	return partition, nil
+}
+
+// write runs write on the repository of the partition. write returns true if it stored some entity.
+// If create is true a missing partition is created, and it is removed again if no write stores an entity in it.
+// The lock of the partitions is not held while write runs, so writes in different partitions do not wait for each
+// other. It returns notFound if the partition does not exist and create is false, and droppedPartition if the
+// partition was dropped while write ran.
+func (r *PartitionedRepository[Partition, Key, Entity]) write(
+	partition Partition,
+	create bool,
+	write func(*InMemoryRepository[Key, Entity]) bool,
+) error {
+	entry, err := r.startWrite(partition, create)
+	if err != nil {
+		return err
+	}
+
+	stored := write(entry.repo)
+
+	return r.endWrite(partition, entry, stored)
+}
+
+func (r *PartitionedRepository[Partition, Key, Entity]) startWrite(partition Partition, create bool) (*partitionEntry[Key, Entity], error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	entry, found := r.partitions[partition]
+	if !found {
+		if !create {
+			return nil, notFound
+		}
+		entry = &partitionEntry[Key, Entity]{repo: r.newPartition()}
+		r.partitions[partition] = entry
+	}
+
+	entry.writers++
+	return entry, nil
+}
+
+func (r *PartitionedRepository[Partition, Key, Entity]) endWrite(partition Partition, entry *partitionEntry[Key, Entity], stored bool) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	entry.writers--
+	entry.stored = entry.stored || stored
+
+	if entry.dropped {
+		return droppedPartition
+	}
+	if !entry.stored && entry.writers == 0 {
+		delete(r.partitions, partition)
+	}
+
+	return nil
+}
+
+func (r *PartitionedRepository[Partition, Key, Entity]) CreateCtx(ctx context.Context, entity Entity) (Entity, error) {
+	partition, err := r.partitionOf(ctx, entity)
+	if err != nil {
+		return entity, err
+	}
+
+	dropped := r.write(partition, true, func(repo *InMemoryRepository[Key, Entity]) bool {
+		entity, err = repo.CreateCtx(ctx, entity)
+		return err == nil
+	})
+
+	return entity, firstError(err, dropped)
+}
+
+// CreateAllCtx creates all the entities atomically. All of them must belong to the same partition.
+func (r *PartitionedRepository[Partition, Key, Entity]) CreateAllCtx(ctx context.Context, entities ...Entity) ([]Entity, []error) {
+	if len(entities) == 0 {
+		return entities, nil
+	}
+
+	partition, err := r.partitionOf(ctx, entities[0])
+	if err != nil {
+		return entities, failAll(len(entities), err)
+	}
+	for _, entity := range entities[1:] {
+		if r.getPartition(entity) != partition {
+			return entities, failAll(len(entities), mixedPartitions)
+		}
+	}
+
+	var results []Entity
+	var errs []error
+
+	dropped := r.write(partition, true, func(repo *InMemoryRepository[Key, Entity]) bool {
+		results, errs = repo.CreateAllCtx(ctx, entities...)
+		return errs[0] == nil
+	})
+
+	return results, reportDropped(errs, dropped)
+}
+
+func (r *PartitionedRepository[Partition, Key, Entity]) UpdateCtx(ctx context.Context, entity Entity) (Entity, error) {
+	partition, err := r.partitionOf(ctx, entity)
+	if err != nil {
+		return entity, err
+	}
+
+	missing := r.write(partition, false, func(repo *InMemoryRepository[Key, Entity]) bool {
+		entity, err = repo.UpdateCtx(ctx, entity)
+		return err == nil
+	})
+
+	return entity, firstError(missing, err)
+}
+
+func (r *PartitionedRepository[Partition, Key, Entity]) UpsertCtx(ctx context.Context, entity Entity) (Entity, error) {
+	partition, err := r.partitionOf(ctx, entity)
+	if err != nil {
+		return entity, err
+	}
+
+	dropped := r.write(partition, true, func(repo *InMemoryRepository[Key, Entity]) bool {
+		entity, err = repo.UpsertCtx(ctx, entity)
+		return err == nil
+	})
+
+	return entity, firstError(err, dropped)
+}
+
+// UpsertAllCtx creates or replaces the entities, each one in its own partition
+func (r *PartitionedRepository[Partition, Key, Entity]) UpsertAllCtx(ctx context.Context, entities ...Entity) ([]Entity, []error) {
+	results := make([]Entity, len(entities))
+	errs := make([]error, len(entities))
+	indexes := make(map[Partition][]int)
+
+	for idx, entity := range entities {
+		partition, err := r.partitionOf(ctx, entity)
+		if err != nil {
+			results[idx], errs[idx] = entity, err
+			continue
+		}
+		indexes[partition] = append(indexes[partition], idx)
+	}
+
+	for partition, idxs := range indexes {
+		group := make([]Entity, len(idxs))
+		for pos, idx := range idxs {
+			group[pos] = entities[idx]
+		}
+
+		var groupErrs []error
+		dropped := r.write(partition, true, func(repo *InMemoryRepository[Key, Entity]) bool {
+			var groupResults []Entity
+			groupResults, groupErrs = repo.UpsertAllCtx(ctx, group...)
+
+			for pos, idx := range idxs {
+				results[idx] = groupResults[pos]
+			}
+			return slices.Any(groupErrs, func(err error) bool { return err == nil })
+		})
+
+		for pos, idx := range idxs {
+			errs[idx] = firstError(groupErrs[pos], dropped)
+		}
+	}
+
+	return results, errs
+}
+
+// firstError returns the first error that is not nil
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reportDropped reports droppedPartition for the entities that were stored in a partition dropped meanwhile
+func reportDropped(errs []error, dropped error) []error {
+	if dropped != nil {
+		for idx := range errs {
+			if errs[idx] == nil {
+				errs[idx] = dropped
+			}
+		}
+	}
+	return errs
+}
+
+func (r *PartitionedRepository[Partition, Key, Entity]) DeleteCtx(ctx context.Context, key Key) error {
+	repo, found, err := r.fromContext(ctx)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return notFound
+	}
+	return repo.DeleteCtx(ctx, key)
+}
+
+func (r *PartitionedRepository[Partition, Key, Entity]) DeleteByCtx(ctx context.Context, predicate types.Predicate[Entity]) (int, error) {
+	repo, found, err := r.fromContext(ctx)
+	if err != nil || !found {
+		return 0, err
+	}
+	return repo.DeleteByCtx(ctx, predicate)
+}
+
+func (r *PartitionedRepository[Partition, Key, Entity]) FindByIDCtx(ctx context.Context, key Key) (Entity, error) {
+	var empty Entity
+
+	repo, found, err := r.fromContext(ctx)
+	if err != nil {
+		return empty, err
+	}
+	if !found {
+		return empty, notFound
+	}
+	return repo.FindByIDCtx(ctx, key)
+}
+
+func (r *PartitionedRepository[Partition, Key, Entity]) FindByCtx(ctx context.Context, predicate types.Predicate[Entity]) ([]Entity, error) {
+	repo, found, err := r.fromContext(ctx)
+	if err != nil || !found {
+		return nil, err
+	}
+	return repo.FindByCtx(ctx, predicate)
+}
+
+func (r *PartitionedRepository[Partition, Key, Entity]) FindOneByCtx(ctx context.Context, predicate types.Predicate[Entity]) (Entity, error) {
+	var empty Entity
+
+	repo, found, err := r.fromContext(ctx)
+	if err != nil {
+		return empty, err
+	}
+	if !found {
+		return empty, notFound
+	}
+	return repo.FindOneByCtx(ctx, predicate)
+}
+
+var _ Repository[string, any] = &PartitionedRepository[string, string, any]{}
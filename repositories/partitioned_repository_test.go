@@ -0,0 +1,171 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tenantEntity struct {
+	Tenant string
+	Id     string
+	Value  int
+}
+
+func newPartitionedRepo() *PartitionedRepository[string, string, *tenantEntity] {
+	return NewPartitionedRepository(
+		func(e *tenantEntity) string { return e.Tenant },
+		func() *InMemoryRepository[string, *tenantEntity] {
+			return &InMemoryRepository[string, *tenantEntity]{
+				GetKey: func(e *tenantEntity) string { return e.Id },
+			}
+		},
+	)
+}
+
+func Test_partitions_have_isolated_keyspaces(t *testing.T) {
+	repo := newPartitionedRepo()
+	ctx := context.Background()
+
+	_, errA := repo.CreateCtx(ctx, &tenantEntity{"tenant-a", Key1, 1})
+	_, errB := repo.CreateCtx(ctx, &tenantEntity{"tenant-b", Key1, 2})
+
+	assert.Nil(t, errA)
+	assert.Nil(t, errB)
+	assert.Equal(t, 2, repo.TotalCount())
+	tenantA, exists := repo.Partition("tenant-a")
+	assert.True(t, exists)
+	assert.Equal(t, 1, tenantA.TotalCount())
+
+	found, err := repo.FindByIDCtx(WithPartition(ctx, "tenant-b"), Key1)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, found.Value)
+}
+
+func Test_queries_are_scoped_to_context_partition(t *testing.T) {
+	repo := newPartitionedRepo()
+	ctx := context.Background()
+	_, _ = repo.UpsertAllCtx(ctx,
+		&tenantEntity{"tenant-a", "a-key-001", 1},
+		&tenantEntity{"tenant-b", "a-key-002", 2},
+		&tenantEntity{"tenant-a", "a-key-003", 3},
+	)
+
+	found, err := repo.FindByCtx(WithPartition(ctx, "tenant-a"), func(*tenantEntity) bool { return true })
+	_, errNoPartition := repo.FindByCtx(ctx, func(*tenantEntity) bool { return true })
+	_, errUnknown := repo.FindByIDCtx(WithPartition(ctx, "tenant-c"), "a-key-001")
+
+	assert.Nil(t, err)
+	assert.Len(t, found, 2)
+	assert.ErrorIs(t, errNoPartition, noPartition)
+	assert.ErrorIs(t, errUnknown, notFound)
+}
+
+func Test_DropPartition_removes_its_entities(t *testing.T) {
+	repo := newPartitionedRepo()
+	ctx := context.Background()
+	_, _ = repo.CreateCtx(ctx, &tenantEntity{"tenant-a", Key1, 1})
+	_, _ = repo.CreateCtx(ctx, &tenantEntity{"tenant-b", Key1, 2})
+
+	dropped := repo.DropPartition("tenant-a")
+
+	assert.True(t, dropped)
+	assert.Equal(t, []string{"tenant-b"}, repo.Partitions())
+	assert.Equal(t, 1, repo.TotalCount())
+	assert.False(t, repo.DropPartition("tenant-a"))
+}
+
+func Test_CreateAllCtx_rejects_mixed_partitions(t *testing.T) {
+	repo := newPartitionedRepo()
+
+	_, errs := repo.CreateAllCtx(context.Background(),
+		&tenantEntity{"tenant-a", "a-key-001", 1},
+		&tenantEntity{"tenant-b", "a-key-002", 2},
+	)
+
+	assert.Equal(t, []error{mixedPartitions, mixedPartitions}, errs)
+	assert.Equal(t, 0, repo.TotalCount())
+}
+
+func Test_writes_reject_entities_of_another_partition(t *testing.T) {
+	repo := newPartitionedRepo()
+	ctx := WithPartition(context.Background(), "tenant-a")
+
+	_, errCreate := repo.CreateCtx(ctx, &tenantEntity{"tenant-b", Key1, 1})
+	_, errUpsert := repo.UpsertCtx(ctx, &tenantEntity{"tenant-b", Key1, 1})
+	_, errUpdate := repo.UpdateCtx(ctx, &tenantEntity{"tenant-b", Key1, 1})
+	_, errsUpsertAll := repo.UpsertAllCtx(ctx, &tenantEntity{"tenant-a", Key1, 1}, &tenantEntity{"tenant-b", Key1, 2})
+
+	assert.ErrorIs(t, errCreate, wrongPartition)
+	assert.ErrorIs(t, errUpsert, wrongPartition)
+	assert.ErrorIs(t, errUpdate, wrongPartition)
+	assert.Equal(t, []error{nil, wrongPartition}, errsUpsertAll)
+	assert.Equal(t, []string{"tenant-a"}, repo.Partitions())
+}
+
+func Test_failed_create_does_not_leave_an_empty_partition(t *testing.T) {
+	repo := newPartitionedRepo()
+	ctx := context.Background()
+
+	_, err := repo.CreateCtx(ctx, &tenantEntity{"tenant-a", "", 1})
+
+	assert.ErrorIs(t, err, invalidKey)
+	assert.Empty(t, repo.Partitions())
+}
+
+func Test_Partition_does_not_create_partitions(t *testing.T) {
+	repo := newPartitionedRepo()
+
+	partition, found := repo.Partition("tenant-a")
+
+	assert.False(t, found)
+	assert.Nil(t, partition)
+	assert.Empty(t, repo.Partitions())
+}
+
+func Test_NewPartitionedRepository_requires_functions(t *testing.T) {
+	assert.Panics(t, func() {
+		NewPartitionedRepository[string, string, *tenantEntity](func(e *tenantEntity) string { return e.Tenant }, nil)
+	})
+}
+
+func Test_write_in_a_new_partition_does_not_block_other_partitions(t *testing.T) {
+	repo := newPartitionedRepo()
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	repo.newPartition = func() *InMemoryRepository[string, *tenantEntity] {
+		return &InMemoryRepository[string, *tenantEntity]{
+			GetKey: func(e *tenantEntity) string { return e.Id },
+			BeforeCreate: func(e *tenantEntity) error {
+				if e.Tenant == "slow" {
+					close(entered)
+					<-release
+				}
+				return nil
+			},
+		}
+	}
+
+	go func() { _, _ = repo.CreateCtx(context.Background(), &tenantEntity{"slow", Key1, 1}) }()
+	<-entered
+	_, err := repo.CreateCtx(context.Background(), &tenantEntity{"fast", Key1, 2})
+	close(release)
+
+	assert.Nil(t, err)
+}
+
+func Test_write_reports_partition_dropped_meanwhile(t *testing.T) {
+	repo := newPartitionedRepo()
+	_, _ = repo.CreateCtx(context.Background(), &tenantEntity{"tenant-a", Key1, 1})
+	partition, _ := repo.Partition("tenant-a")
+	partition.BeforeUpdate = func(*tenantEntity, *tenantEntity) error {
+		repo.DropPartition("tenant-a")
+		return nil
+	}
+
+	_, err := repo.UpdateCtx(context.Background(), &tenantEntity{"tenant-a", Key1, 2})
+
+	assert.ErrorIs(t, err, droppedPartition)
+	assert.Empty(t, repo.Partitions())
+}
@@ -0,0 +1,293 @@
+package repositories
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/totemcaf/gollections/maps"
+)
+
+// Encoder writes values to a stream. Both json.Encoder and gob.Encoder satisfy it.
+type Encoder interface {
+	Encode(v any) error
+}
+
+// Decoder reads values from a stream. Both json.Decoder and gob.Decoder satisfy it.
+type Decoder interface {
+	Decode(v any) error
+}
+
+// Codec creates encoders and decoders used to persist a repository
+type Codec interface {
+	NewEncoder(w io.Writer) Encoder
+	NewDecoder(r io.Reader) Decoder
+}
+
+// JSONCodec persists entities as JSON. It is the default codec.
+type JSONCodec struct{}
+
+func (JSONCodec) NewEncoder(w io.Writer) Encoder {
+	return json.NewEncoder(w)
+}
+
+func (JSONCodec) NewDecoder(r io.Reader) Decoder {
+	return json.NewDecoder(r)
+}
+
+// GobCodec persists entities using encoding/gob
+type GobCodec struct{}
+
+func (GobCodec) NewEncoder(w io.Writer) Encoder {
+	return gob.NewEncoder(w)
+}
+
+func (GobCodec) NewDecoder(r io.Reader) Decoder {
+	return gob.NewDecoder(r)
+}
+
+func (r *InMemoryRepository[Key, Entity]) codec() Codec {
+	if r.Codec == nil {
+		return JSONCodec{}
+	}
+	return r.Codec
+}
+
+// SaveTo writes a snapshot of all the entities to w. Soft deleted entities are not included.
+func (r *InMemoryRepository[Key, Entity]) SaveTo(w io.Writer) error {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.codec().NewEncoder(w).Encode(maps.Values(r.elementsById))
+}
+
+// LoadFrom replaces the content of the repository with the snapshot read from reader.
+// If the snapshot has invalid or duplicated keys, the repository is not modified. Watchers are not notified.
+// If a write-ahead log is open, it is compacted so it holds the loaded entities; if that fails the repository
+// is not modified.
+func (r *InMemoryRepository[Key, Entity]) LoadFrom(reader io.Reader) error {
+	var entities []Entity
+
+	if err := r.codec().NewDecoder(reader).Decode(&entities); err != nil {
+		return err
+	}
+
+	elements := make(map[Key]Entity, len(entities))
+
+	for _, entity := range entities {
+		key := r.GetKey(entity)
+		if !r.AllowEmptyKey && key == r.emptyKey {
+			return invalidKey
+		}
+		if _, found := elements[key]; found {
+			return duplicateKey
+		}
+		elements[key] = entity
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	previous, previousDeleted := r.elementsById, r.deletedById
+	r.elementsById = elements
+	r.deletedById = make(map[Key]Entity)
+
+	if r.log != nil {
+		if err := r.compactLog(); err != nil {
+			r.elementsById, r.deletedById = previous, previousDeleted
+			return err
+		}
+	}
+
+	return nil
+}
+
+// logRecord is each entry of the write-ahead log
+type logRecord[Key comparable, Entity any] struct {
+	Type   EventType
+	Key    Key
+	Entity Entity
+}
+
+type writeAheadLog struct {
+	path         string
+	file         *os.File
+	encoder      Encoder
+	records      int
+	compactEvery int
+}
+
+// OpenLog enables an append-only write-ahead log stored at path.
+// If the file exists, its records are replayed and replace the content of the repository.
+// Every change is appended to the log before it is applied. After compactEvery records, the log is rewritten
+// with only the current entities. If compactEvery is zero or less, the log is only compacted when it is opened.
+func (r *InMemoryRepository[Key, Entity]) OpenLog(path string, compactEvery int) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.log != nil {
+		return errors.New("log already open")
+	}
+
+	elements, deleted, err := r.replayLog(path)
+	if err != nil {
+		return err
+	}
+
+	r.elementsById = elements
+	r.deletedById = deleted
+	r.log = &writeAheadLog{path: path, compactEvery: compactEvery}
+
+	if err := r.compactLog(); err != nil {
+		r.log = nil
+		return err
+	}
+
+	return nil
+}
+
+// CloseLog stops writing the write-ahead log
+func (r *InMemoryRepository[Key, Entity]) CloseLog() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.log == nil {
+		return nil
+	}
+
+	err := r.log.file.Close()
+	r.log = nil
+
+	return err
+}
+
+// CompactLog rewrites the write-ahead log with only the current entities
+func (r *InMemoryRepository[Key, Entity]) CompactLog() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.log == nil {
+		return nil
+	}
+
+	return r.compactLog()
+}
+
+func (r *InMemoryRepository[Key, Entity]) replayLog(path string) (map[Key]Entity, map[Key]Entity, error) {
+	elements := make(map[Key]Entity, 16)
+	deleted := make(map[Key]Entity)
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return elements, deleted, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	decoder := r.codec().NewDecoder(file)
+
+	for {
+		var record logRecord[Key, Entity]
+
+		err := decoder.Decode(&record)
+		// A truncated last record means the process stopped while writing it, so it was never applied
+		if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+			return elements, deleted, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch record.Type {
+		case Deleted:
+			delete(elements, record.Key)
+			if r.SoftDelete {
+				deleted[record.Key] = record.Entity
+			}
+		case Restored:
+			delete(deleted, record.Key)
+			elements[record.Key] = record.Entity
+		case Purged:
+			delete(deleted, record.Key)
+		default:
+			elements[record.Key] = record.Entity
+		}
+	}
+}
+
+// compactLog writes current entities into a new file that replaces the log. It must be called with the write lock held.
+// If it fails the previous log is kept.
+func (r *InMemoryRepository[Key, Entity]) compactLog() error {
+	tmpPath := r.log.path + ".tmp"
+
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+
+	encoder := r.codec().NewEncoder(file)
+
+	for key, entity := range r.elementsById {
+		if err := encoder.Encode(logRecord[Key, Entity]{Type: Created, Key: key, Entity: entity}); err != nil {
+			_ = file.Close()
+			return err
+		}
+	}
+
+	for key, entity := range r.deletedById {
+		if err := encoder.Encode(logRecord[Key, Entity]{Type: Deleted, Key: key, Entity: entity}); err != nil {
+			_ = file.Close()
+			return err
+		}
+	}
+
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	if err := os.Rename(tmpPath, r.log.path); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	if r.log.file != nil {
+		_ = r.log.file.Close()
+	}
+
+	r.log.file = file
+	r.log.encoder = encoder
+	r.log.records = 0
+
+	return nil
+}
+
+func (r *InMemoryRepository[Key, Entity]) appendLog(event Event[Key, Entity]) error {
+	if r.log == nil {
+		return nil
+	}
+
+	record := logRecord[Key, Entity]{Type: event.Type, Key: event.Key, Entity: event.New}
+
+	if err := r.log.encoder.Encode(record); err != nil {
+		return err
+	}
+
+	r.log.records++
+
+	return nil
+}
+
+// compactLogIfNeeded compacts the log when it has too many records. A failure is not reported because the
+// change was already logged; compaction is retried after the next change.
+func (r *InMemoryRepository[Key, Entity]) compactLogIfNeeded() {
+	if r.log == nil || r.log.compactEvery <= 0 || r.log.records < r.log.compactEvery {
+		return
+	}
+
+	_ = r.compactLog()
+}
@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SaveTo_and_LoadFrom_keeps_entities(t *testing.T) {
+	for name, codec := range map[string]Codec{"json": JSONCodec{}, "gob": GobCodec{}} {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo()
+			repo.Codec = codec
+			_, _ = repo.Create(&entity{"a-key-001", 4200})
+			_, _ = repo.Create(&entity{"a-key-002", 42})
+
+			var buffer bytes.Buffer
+			assert.Nil(t, repo.SaveTo(&buffer))
+
+			loaded := newRepo()
+			loaded.Codec = codec
+			assert.Nil(t, loaded.LoadFrom(&buffer))
+
+			assert.Equal(t, 2, loaded.TotalCount())
+			found, err := loaded.FindByID("a-key-001")
+			assert.Nil(t, err)
+			assert.Equal(t, &entity{"a-key-001", 4200}, found)
+		})
+	}
+}
+
+func Test_LoadFrom_rejects_duplicated_keys(t *testing.T) {
+	repo := newRepo()
+	_, _ = repo.Create(&entity{Key1, 42})
+
+	err := repo.LoadFrom(bytes.NewBufferString(`[{"Id":"a"},{"Id":"a"}]`))
+
+	assert.ErrorIs(t, err, duplicateKey)
+	assert.Equal(t, 1, repo.TotalCount())
+}
+
+func Test_OpenLog_restores_changes_after_restart(t *testing.T) {
+	for name, codec := range map[string]Codec{"json": JSONCodec{}, "gob": GobCodec{}} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "repo.log")
+
+			repo := newRepo()
+			repo.Codec = codec
+			assert.Nil(t, repo.OpenLog(path, 0))
+			_, _ = repo.Create(&entity{"a-key-001", 1})
+			_, _ = repo.Create(&entity{"a-key-002", 2})
+			_, _ = repo.Update(&entity{"a-key-001", 11})
+			_ = repo.Delete("a-key-002")
+			assert.Nil(t, repo.CloseLog())
+
+			restarted := newRepo()
+			restarted.Codec = codec
+			assert.Nil(t, restarted.OpenLog(path, 0))
+			defer func() { _ = restarted.CloseLog() }()
+
+			assert.Equal(t, 1, restarted.TotalCount())
+			found, err := restarted.FindByID("a-key-001")
+			assert.Nil(t, err)
+			assert.Equal(t, 11, found.Value)
+		})
+	}
+}
+
+func Test_LoadFrom_with_open_log_survives_restart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "repo.log")
+
+	repo := newRepo()
+	assert.Nil(t, repo.OpenLog(path, 0))
+	_, _ = repo.Create(&entity{"before-load", 1})
+
+	assert.Nil(t, repo.LoadFrom(bytes.NewBufferString(`[{"Id":"loaded","Value":2}]`)))
+	_, _ = repo.Create(&entity{"after-load", 3})
+	assert.Nil(t, repo.CloseLog())
+
+	restarted := newRepo()
+	assert.Nil(t, restarted.OpenLog(path, 0))
+	defer func() { _ = restarted.CloseLog() }()
+
+	assert.Equal(t, 2, restarted.TotalCount())
+	_, err := restarted.FindByID("before-load")
+	assert.Error(t, err)
+	loaded, err := restarted.FindByID("loaded")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, loaded.Value)
+}
+
+func Test_OpenLog_compacts_log(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "repo.log")
+
+	repo := newRepo()
+	assert.Nil(t, repo.OpenLog(path, 3))
+	defer func() { _ = repo.CloseLog() }()
+
+	_, _ = repo.Create(&entity{Key1, 1})
+	_, _ = repo.Update(&entity{Key1, 2})
+	_, _ = repo.Update(&entity{Key1, 3})
+
+	content, err := os.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"Type":1,"Key":"key-1","Entity":{"Id":"key-1","Value":3}}`+"\n", string(content))
+}
+
+func Test_OpenLog_restores_soft_deleted_entities(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "repo.log")
+
+	repo := newRepo()
+	repo.SoftDelete = true
+	assert.Nil(t, repo.OpenLog(path, 0))
+	_, _ = repo.CreateAll(&entity{"a-key-001", 1}, &entity{"a-key-002", 2}, &entity{"a-key-003", 3})
+	_ = repo.Delete("a-key-001")
+	_ = repo.Delete("a-key-002")
+	_ = repo.Purge("a-key-002")
+	assert.Nil(t, repo.CloseLog())
+
+	restarted := newRepo()
+	restarted.SoftDelete = true
+	assert.Nil(t, restarted.OpenLog(path, 0))
+	defer func() { _ = restarted.CloseLog() }()
+
+	assert.Equal(t, 1, restarted.TotalCount())
+	assert.Equal(t, 1, restarted.DeletedCount())
+	_, err := restarted.Restore("a-key-001")
+	assert.Nil(t, err)
+}
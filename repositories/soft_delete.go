@@ -0,0 +1,168 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/totemcaf/gollections/maps"
+	"github.com/totemcaf/gollections/slices"
+	"github.com/totemcaf/gollections/types"
+)
+
+// Restore makes visible again a soft deleted entity
+func (r *InMemoryRepository[Key, Entity]) Restore(key Key) (Entity, error) {
+	return r.RestoreCtx(context.Background(), key)
+}
+
+// RestoreCtx is like Restore but gives up if ctx is done before the operation starts
+func (r *InMemoryRepository[Key, Entity]) RestoreCtx(ctx context.Context, key Key) (Entity, error) {
+	var empty Entity
+
+	if err := r.Faults.inject(ctx, OpRestore, key); err != nil {
+		return empty, err
+	}
+
+	if err := r.lockCtx(ctx); err != nil {
+		return empty, err
+	}
+	defer r.lock.Unlock()
+	r.init()
+
+	entity, found := r.deletedById[key]
+	if !found {
+		return entity, notFound
+	}
+
+	entity = r.clone(entity)
+	if r.SetDeletedAt != nil {
+		entity = r.SetDeletedAt(entity, time.Time{})
+	}
+	entity = r.stampUpdated(entity)
+
+	return entity, r.apply(Event[Key, Entity]{Type: Restored, Key: key, New: entity})
+}
+
+// Purge removes for good a soft deleted entity. Its key can be used again.
+func (r *InMemoryRepository[Key, Entity]) Purge(key Key) error {
+	return r.PurgeCtx(context.Background(), key)
+}
+
+// PurgeCtx is like Purge but gives up if ctx is done before the operation starts
+func (r *InMemoryRepository[Key, Entity]) PurgeCtx(ctx context.Context, key Key) error {
+	if err := r.Faults.inject(ctx, OpPurge, key); err != nil {
+		return err
+	}
+
+	if err := r.lockCtx(ctx); err != nil {
+		return err
+	}
+	defer r.lock.Unlock()
+	r.init()
+
+	entity, found := r.deletedById[key]
+	if !found {
+		return notFound
+	}
+
+	return r.apply(Event[Key, Entity]{Type: Purged, Key: key, Old: entity})
+}
+
+// PurgeDeleted removes for good all the soft deleted entities. Returns how many were removed. It cannot report
+// errors, use PurgeDeletedCtx to get them.
+func (r *InMemoryRepository[Key, Entity]) PurgeDeleted() int {
+	count, _ := r.PurgeDeletedCtx(context.Background())
+	return count
+}
+
+// PurgeDeletedCtx is like PurgeDeleted but gives up if ctx is done before the operation starts. It stops at the
+// first entity that cannot be purged, for example because the write-ahead log cannot be written, and returns the
+// error with how many were removed.
+func (r *InMemoryRepository[Key, Entity]) PurgeDeletedCtx(ctx context.Context) (int, error) {
+	if err := r.Faults.inject(ctx, OpPurgeDeleted, nil); err != nil {
+		return 0, err
+	}
+
+	if err := r.lockCtx(ctx); err != nil {
+		return 0, err
+	}
+	defer r.lock.Unlock()
+	r.init()
+
+	count := 0
+
+	for key, entity := range r.deletedById {
+		if err := r.apply(Event[Key, Entity]{Type: Purged, Key: key, Old: entity}); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// FindDeletedBy returns the soft deleted entities that satisfy predicate
+func (r *InMemoryRepository[Key, Entity]) FindDeletedBy(predicate types.Predicate[Entity]) []Entity {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.cloneAll(slices.Filter(maps.Values(r.deletedById), predicate))
+}
+
+// DeletedCount returns the number of soft deleted entities
+func (r *InMemoryRepository[Key, Entity]) DeletedCount() int {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return len(r.deletedById)
+}
+
+// keyInUse returns true if the key belongs to an entity, even a soft deleted one
+func (r *InMemoryRepository[Key, Entity]) keyInUse(key Key) bool {
+	if _, found := r.elementsById[key]; found {
+		return true
+	}
+	_, found := r.deletedById[key]
+	return found
+}
+
+// deletion returns the event that deletes the entity, stamping it if the repository uses soft delete
+func (r *InMemoryRepository[Key, Entity]) deletion(key Key, entity Entity) Event[Key, Entity] {
+	event := Event[Key, Entity]{Type: Deleted, Key: key, Old: entity}
+
+	if r.SoftDelete {
+		event.New = r.clone(entity)
+		if r.SetDeletedAt != nil {
+			event.New = r.SetDeletedAt(event.New, r.now())
+		}
+	}
+
+	return event
+}
+
+func (r *InMemoryRepository[Key, Entity]) now() time.Time {
+	if r.Clock == nil {
+		return time.Now()
+	}
+	return r.Clock()
+}
+
+func (r *InMemoryRepository[Key, Entity]) stampCreated(entity Entity) Entity {
+	now := r.now()
+
+	if r.SetCreatedAt != nil {
+		entity = r.SetCreatedAt(entity, now)
+	}
+	if r.SetUpdatedAt != nil {
+		entity = r.SetUpdatedAt(entity, now)
+	}
+
+	return entity
+}
+
+func (r *InMemoryRepository[Key, Entity]) stampUpdated(entity Entity) Entity {
+	if r.SetUpdatedAt != nil {
+		entity = r.SetUpdatedAt(entity, r.now())
+	}
+
+	return entity
+}
@@ -0,0 +1,145 @@
+package repositories
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type audited struct {
+	Id        string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt time.Time
+}
+
+var now = time.Date(2022, 7, 1, 10, 0, 0, 0, time.UTC)
+
+func newAuditedRepo(clock *time.Time) *InMemoryRepository[string, audited] {
+	return &InMemoryRepository[string, audited]{
+		GetKey:     func(e audited) string { return e.Id },
+		SoftDelete: true,
+		Clock:      func() time.Time { return *clock },
+		SetCreatedAt: func(e audited, t time.Time) audited {
+			e.CreatedAt = t
+			return e
+		},
+		SetUpdatedAt: func(e audited, t time.Time) audited {
+			e.UpdatedAt = t
+			return e
+		},
+		SetDeletedAt: func(e audited, t time.Time) audited {
+			e.DeletedAt = t
+			return e
+		},
+	}
+}
+
+func Test_soft_deleted_entity_is_hidden(t *testing.T) {
+	repo := newRepo()
+	repo.SoftDelete = true
+	_, _ = repo.Create(&entity{Key1, 42})
+
+	err := repo.Delete(Key1)
+
+	assert.Nil(t, err)
+	_, err = repo.FindByID(Key1)
+	assert.ErrorIs(t, err, notFound)
+	assert.Empty(t, repo.FindBy(func(*entity) bool { return true }))
+	assert.Equal(t, 0, repo.TotalCount())
+	assert.Equal(t, 1, repo.DeletedCount())
+}
+
+func Test_soft_deleted_key_cannot_be_reused_until_purged(t *testing.T) {
+	repo := newRepo()
+	repo.SoftDelete = true
+	_, _ = repo.Create(&entity{Key1, 42})
+	_ = repo.Delete(Key1)
+
+	_, err := repo.Create(&entity{Key1, 43})
+	assert.ErrorIs(t, err, duplicateKey)
+
+	assert.Nil(t, repo.Purge(Key1))
+	_, err = repo.Create(&entity{Key1, 43})
+	assert.Nil(t, err)
+}
+
+func Test_Restore_makes_entity_visible_again(t *testing.T) {
+	clock := now
+	repo := newAuditedRepo(&clock)
+	_, _ = repo.Create(audited{Id: Key1})
+
+	clock = now.Add(time.Hour)
+	_ = repo.Delete(Key1)
+	deleted := repo.FindDeletedBy(func(audited) bool { return true })
+	assert.Equal(t, clock, deleted[0].DeletedAt)
+
+	clock = now.Add(2 * time.Hour)
+	restored, err := repo.Restore(Key1)
+
+	assert.Nil(t, err)
+	assert.Equal(t, audited{Id: Key1, CreatedAt: now, UpdatedAt: clock}, restored)
+	assert.Equal(t, 1, repo.TotalCount())
+	assert.Equal(t, 0, repo.DeletedCount())
+}
+
+func Test_Create_and_Update_stamp_times(t *testing.T) {
+	clock := now
+	repo := newAuditedRepo(&clock)
+
+	created, _ := repo.Create(audited{Id: Key1})
+	assert.Equal(t, now, created.CreatedAt)
+	assert.Equal(t, now, created.UpdatedAt)
+
+	clock = now.Add(time.Minute)
+	updated, _ := repo.Update(created)
+	assert.Equal(t, now, updated.CreatedAt)
+	assert.Equal(t, clock, updated.UpdatedAt)
+}
+
+func Test_PurgeDeleted_removes_all_deleted(t *testing.T) {
+	repo := newRepo()
+	repo.SoftDelete = true
+	_, _ = repo.CreateAll(&entity{"a-key-001", 1}, &entity{"a-key-002", 2}, &entity{"a-key-003", 3})
+	_ = repo.DeleteBy(func(e *entity) bool { return e.Value > 1 })
+
+	count := repo.PurgeDeleted()
+
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 0, repo.DeletedCount())
+	assert.Equal(t, 1, repo.TotalCount())
+}
+
+func Test_PurgeDeletedCtx_reports_faults(t *testing.T) {
+	repo := newRepo()
+	repo.SoftDelete = true
+	_, _ = repo.Create(&entity{Key1, 1})
+	_ = repo.Delete(Key1)
+	repo.Faults = NewFaultInjector().FailOperation(OpPurgeDeleted, unavailable)
+
+	count, err := repo.PurgeDeletedCtx(context.Background())
+
+	assert.ErrorIs(t, err, unavailable)
+	assert.Equal(t, 0, count)
+	assert.Equal(t, 1, repo.DeletedCount())
+}
+
+func Test_PurgeDeletedCtx_reports_log_errors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "repo.log")
+	repo := newRepo()
+	repo.SoftDelete = true
+	repo.Codec = failingCodec{written: new(int), failAt: 3}
+	assert.Nil(t, repo.OpenLog(path, 0))
+	defer func() { _ = repo.CloseLog() }()
+	_, _ = repo.Create(&entity{Key1, 1})
+	_ = repo.Delete(Key1)
+
+	count, err := repo.PurgeDeletedCtx(context.Background())
+
+	assert.EqualError(t, err, "disk full")
+	assert.Equal(t, 0, count)
+	assert.Equal(t, 1, repo.DeletedCount())
+}
@@ -0,0 +1,207 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/totemcaf/gollections/sets"
+	"github.com/totemcaf/gollections/types"
+)
+
+// EventType tells which kind of change an Event reports
+type EventType int
+
+const (
+	Created EventType = iota + 1
+	Updated
+	Deleted
+	// Restored reports a soft deleted entity that is visible again
+	Restored
+	// Purged reports a soft deleted entity that was removed for good
+	Purged
+)
+
+// String returns the name of the event type
+func (t EventType) String() string {
+	switch t {
+	case Created:
+		return "Created"
+	case Updated:
+		return "Updated"
+	case Deleted:
+		return "Deleted"
+	case Restored:
+		return "Restored"
+	case Purged:
+		return "Purged"
+	default:
+		return "Unknown"
+	}
+}
+
+// Event describes a change committed to the repository.
+// Old is empty for Created and Restored events, New is empty for Purged events and for Deleted events unless the
+// repository uses soft delete, then New is the entity as kept apart.
+type Event[Key comparable, Entity any] struct {
+	Type EventType
+	Key  Key
+	Old  Entity
+	New  Entity
+}
+
+// OverflowPolicy tells what to do when a watcher buffer is full
+type OverflowPolicy int
+
+const (
+	// Block makes the writer wait until the watcher receives the event or its context is done. The writer holds the
+	// repository lock while it waits, so a slow watcher stalls all the readers and writers of the repository, and a
+	// watcher that calls the repository before receiving the event deadlocks it.
+	Block OverflowPolicy = iota
+	// DropNewest discards the event that does not fit in the buffer
+	DropNewest
+	// DropOldest discards the oldest buffered event to make room for the new one
+	DropOldest
+)
+
+// WatchOptions configures a watch subscription
+type WatchOptions struct {
+	// Buffer is the capacity of the events channel
+	Buffer int
+	// Overflow is applied when the buffer is full
+	Overflow OverflowPolicy
+}
+
+// DefaultWatchOptions are used by Watch
+var DefaultWatchOptions = WatchOptions{Buffer: 64, Overflow: DropOldest}
+
+var negativeBuffer = errors.New("watch buffer cannot be negative")
+
+type watcher[Key comparable, Entity any] struct {
+	ctx       context.Context
+	events    chan Event[Key, Entity]
+	predicate types.Predicate[Entity]
+	overflow  OverflowPolicy
+}
+
+// Watch streams the changes of entities that satisfy predicate, in commit order, until ctx is done.
+// Updates are reported if either the old or the new value satisfies predicate. A nil predicate matches all.
+// The channel is closed when ctx is done.
+//
+// Events are delivered while the repository lock is held, to keep them in commit order. Watch uses
+// DefaultWatchOptions, so writers never wait for the subscriber: when the buffer is full the oldest event is dropped.
+func (r *InMemoryRepository[Key, Entity]) Watch(
+	ctx context.Context,
+	predicate types.Predicate[Entity],
+) <-chan Event[Key, Entity] {
+	events, _ := r.WatchWith(ctx, predicate, DefaultWatchOptions)
+	return events
+}
+
+// WatchWith is like Watch but allows to configure buffering and what to do with slow subscribers. It fails if the
+// buffer is negative.
+// As in Watch, events are delivered with the repository lock held, so a subscriber with the Block policy and a full
+// buffer stalls all the readers and writers of the repository.
+func (r *InMemoryRepository[Key, Entity]) WatchWith(
+	ctx context.Context,
+	predicate types.Predicate[Entity],
+	options WatchOptions,
+) (<-chan Event[Key, Entity], error) {
+	if options.Buffer < 0 {
+		return nil, negativeBuffer
+	}
+
+	w := &watcher[Key, Entity]{
+		ctx:       ctx,
+		events:    make(chan Event[Key, Entity], options.Buffer),
+		predicate: predicate,
+		overflow:  options.Overflow,
+	}
+
+	r.lock.Lock()
+	if r.watchers == nil {
+		r.watchers = sets.New[*watcher[Key, Entity]]()
+	}
+	r.watchers.Add(w)
+	r.lock.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		r.lock.Lock()
+		defer r.lock.Unlock()
+
+		r.watchers.Remove(w)
+		close(w.events)
+	}()
+
+	return w.events, nil
+}
+
+// publish sends the event to all interested watchers. It must be called with the write lock held.
+func (r *InMemoryRepository[Key, Entity]) publish(event Event[Key, Entity]) {
+	for w := range r.watchers {
+		if w.matches(event) {
+			w.send(r.cloneEvent(event))
+		}
+	}
+}
+
+// cloneEvent gives each watcher its own copy of the entities when the repository isolates entities
+func (r *InMemoryRepository[Key, Entity]) cloneEvent(event Event[Key, Entity]) Event[Key, Entity] {
+	if r.Isolate {
+		event.Old = r.clone(event.Old)
+		event.New = r.clone(event.New)
+	}
+	return event
+}
+
+func (w *watcher[Key, Entity]) matches(event Event[Key, Entity]) bool {
+	if w.predicate == nil {
+		return true
+	}
+
+	switch event.Type {
+	case Created, Restored:
+		return w.predicate(event.New)
+	case Deleted, Purged:
+		return w.predicate(event.Old)
+	default:
+		return w.predicate(event.Old) || w.predicate(event.New)
+	}
+}
+
+func (w *watcher[Key, Entity]) send(event Event[Key, Entity]) {
+	if w.ctx.Err() != nil {
+		return
+	}
+
+	switch w.overflow {
+	case DropNewest:
+		select {
+		case w.events <- event:
+		default:
+		}
+	case DropOldest:
+		// with an unbuffered channel there is nothing old to drop
+		for cap(w.events) > 0 {
+			select {
+			case w.events <- event:
+				return
+			default:
+			}
+			select {
+			case <-w.events:
+			default:
+			}
+		}
+		select {
+		case w.events <- event:
+		default:
+		}
+	default:
+		select {
+		case w.events <- event:
+		case <-w.ctx.Done():
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Watch_reports_changes_in_commit_order(t *testing.T) {
+	repo := newRepo()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := repo.Watch(ctx, nil)
+
+	created := &entity{Key1, 42}
+	updated := &entity{Key1, 4242}
+	_, _ = repo.Create(created)
+	_, _ = repo.Update(updated)
+	_ = repo.Delete(Key1)
+
+	assert.Equal(t, Event[string, *entity]{Type: Created, Key: Key1, New: created}, <-events)
+	assert.Equal(t, Event[string, *entity]{Type: Updated, Key: Key1, Old: created, New: updated}, <-events)
+	assert.Equal(t, Event[string, *entity]{Type: Deleted, Key: Key1, Old: updated}, <-events)
+}
+
+func Test_Watch_only_reports_entities_that_satisfy_predicate(t *testing.T) {
+	repo := newRepo()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := repo.Watch(ctx, func(e *entity) bool { return e.Value > 100 })
+
+	_, _ = repo.Create(&entity{"a-key-001", 42})
+	_, _ = repo.Create(&entity{"a-key-002", 4200})
+
+	event := <-events
+	assert.Equal(t, "a-key-002", event.Key)
+	assert.Len(t, events, 0)
+}
+
+func Test_Watch_closes_channel_when_context_is_done(t *testing.T) {
+	repo := newRepo()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	events := repo.Watch(ctx, nil)
+	cancel()
+
+	_, open := <-events
+	assert.False(t, open)
+}
+
+func Test_WatchWith_DropNewest_does_not_block_writer(t *testing.T) {
+	repo := newRepo()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := repo.WatchWith(ctx, nil, WatchOptions{Buffer: 1, Overflow: DropNewest})
+	assert.Nil(t, err)
+
+	_, _ = repo.Create(&entity{"a-key-001", 1})
+	_, _ = repo.Create(&entity{"a-key-002", 2})
+
+	assert.Equal(t, "a-key-001", (<-events).Key)
+	assert.Len(t, events, 0)
+}
+
+func Test_WatchWith_DropOldest_keeps_latest_events(t *testing.T) {
+	repo := newRepo()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := repo.WatchWith(ctx, nil, WatchOptions{Buffer: 2, Overflow: DropOldest})
+	assert.Nil(t, err)
+
+	_, _ = repo.Create(&entity{"a-key-001", 1})
+	_, _ = repo.Create(&entity{"a-key-002", 2})
+	_, _ = repo.Create(&entity{"a-key-003", 3})
+
+	assert.Equal(t, "a-key-002", (<-events).Key)
+	assert.Equal(t, "a-key-003", (<-events).Key)
+}
+
+func Test_Watch_does_not_block_writer_by_default(t *testing.T) {
+	repo := newRepo()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := repo.Watch(ctx, nil)
+
+	for idx := 0; idx <= DefaultWatchOptions.Buffer; idx++ {
+		_, err := repo.Create(&entity{fmt.Sprintf("a-key-%03d", idx), idx})
+		assert.Nil(t, err)
+	}
+
+	assert.Equal(t, "a-key-001", (<-events).Key)
+	assert.Len(t, events, DefaultWatchOptions.Buffer-1)
+}
+
+func Test_WatchWith_rejects_negative_buffer(t *testing.T) {
+	repo := newRepo()
+
+	events, err := repo.WatchWith(context.Background(), nil, WatchOptions{Buffer: -1})
+
+	assert.ErrorIs(t, err, negativeBuffer)
+	assert.Nil(t, events)
+}
@@ -0,0 +1,152 @@
+package sets
+
+import "github.com/totemcaf/gollections/types"
+
+// Pair is an element of the cartesian product of two sets
+type Pair[A comparable, B comparable] struct {
+	First  A
+	Second B
+}
+
+// UnionAll returns a new set with the elements of all the given sets
+func UnionAll[T comparable](sets ...Set[T]) Set[T] {
+	union := New[T]()
+	for _, s := range sets {
+		for v := range s {
+			union.Add(v)
+		}
+	}
+	return union
+}
+
+// IntersectAll returns a new set with the elements that are in all the given sets.
+// The intersection of no sets is empty.
+func IntersectAll[T comparable](sets ...Set[T]) Set[T] {
+	if len(sets) == 0 {
+		return New[T]()
+	}
+
+	smallest := sets[0]
+	for _, s := range sets[1:] {
+		if s.Size() < smallest.Size() {
+			smallest = s
+		}
+	}
+
+	return smallest.Filter(func(v T) bool {
+		for _, s := range sets {
+			if !s.Contains(v) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// PowerSet returns all the subsets of the set, including the empty set and the set itself.
+// The result has 2^Size elements, so it is only practical for small sets.
+func PowerSet[T comparable](s Set[T]) []Set[T] {
+	subsets := []Set[T]{New[T]()}
+
+	for v := range s {
+		for _, subset := range subsets {
+			withV := subset.Copy()
+			withV.Add(v)
+			subsets = append(subsets, withV)
+		}
+	}
+
+	return subsets
+}
+
+// CartesianProduct returns all the pairs with the first element from a and the second one from b
+func CartesianProduct[A comparable, B comparable](a Set[A], b Set[B]) Set[Pair[A, B]] {
+	product := make(Set[Pair[A, B]], a.Size()*b.Size())
+	for first := range a {
+		for second := range b {
+			product.Add(Pair[A, B]{first, second})
+		}
+	}
+	return product
+}
+
+// Map returns a new set with the result of applying mapper to all the elements. Elements mapped to the same value
+// are only added once.
+// This is implemented as a function because GO generics does not support (yet) the use of type parameters in
+// methods signatures.
+func Map[T comparable, U comparable](s Set[T], mapper types.Mapper[T, U]) Set[U] {
+	mapped := make(Set[U], s.Size())
+	for v := range s {
+		mapped.Add(mapper(v))
+	}
+	return mapped
+}
+
+// Reduce converts the set in a single value, starting with initialValue. Elements are visited in no particular order.
+// This is implemented as a function because GO generics does not support (yet) the use of type parameters in
+// methods signatures.
+func Reduce[Value any, T comparable](initialValue Value, s Set[T], reducer func(Value, T) Value) Value {
+	accum := initialValue
+	for v := range s {
+		accum = reducer(accum, v)
+	}
+	return accum
+}
+
+// IsDisjoint returns true if this set and the other set have no elements in common
+func (s Set[T]) IsDisjoint(other Set[T]) bool {
+	smaller, bigger := s, other
+	if bigger.Size() < smaller.Size() {
+		smaller, bigger = bigger, smaller
+	}
+	for v := range smaller {
+		if bigger.Contains(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// Partition returns a new set with the elements that satisfy predicate and another one with the rest
+func (s Set[T]) Partition(predicate types.Predicate[T]) (Set[T], Set[T]) {
+	matching, rest := New[T](), New[T]()
+	for v := range s {
+		if predicate(v) {
+			matching.Add(v)
+		} else {
+			rest.Add(v)
+		}
+	}
+	return matching, rest
+}
+
+// Filter returns a new set with the elements that satisfy predicate
+func (s Set[T]) Filter(predicate types.Predicate[T]) Set[T] {
+	filtered := New[T]()
+	for v := range s {
+		if predicate(v) {
+			filtered.Add(v)
+		}
+	}
+	return filtered
+}
+
+// Any returns true if at least one element satisfies predicate
+func (s Set[T]) Any(predicate types.Predicate[T]) bool {
+	for v := range s {
+		if predicate(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// All returns true if all the elements satisfy predicate
+func (s Set[T]) All(predicate types.Predicate[T]) bool {
+	for v := range s {
+		if !predicate(v) {
+			return false
+		}
+	}
+	return true
+}
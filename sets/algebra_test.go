@@ -0,0 +1,67 @@
+package sets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func isEven(n int) bool { return n%2 == 0 }
+
+func TestUnionAll(t *testing.T) {
+	union := UnionAll(Of(1, 2), Of(2, 3), Of(4))
+
+	assert.Equal(t, Of(1, 2, 3, 4), union)
+}
+
+func TestIntersectAll(t *testing.T) {
+	assert.Equal(t, Of(2, 3), IntersectAll(Of(1, 2, 3), Of(2, 3, 4), Of(0, 2, 3, 5)))
+	assert.Equal(t, New[int](), IntersectAll[int]())
+}
+
+func TestSet_IsDisjoint(t *testing.T) {
+	assert.True(t, Of(1, 2).IsDisjoint(Of(3, 4)))
+	assert.False(t, Of(1, 2).IsDisjoint(Of(2, 3)))
+	assert.True(t, New[int]().IsDisjoint(Of(1)))
+}
+
+func TestPowerSet(t *testing.T) {
+	subsets := PowerSet(Of("a", "b", "c"))
+
+	assert.Len(t, subsets, 8)
+	assert.Contains(t, subsets, New[string]())
+	assert.Contains(t, subsets, Of("a", "c"))
+	assert.Contains(t, subsets, Of("a", "b", "c"))
+}
+
+func TestCartesianProduct(t *testing.T) {
+	product := CartesianProduct(Of(1, 2), Of("x", "y"))
+
+	assert.Equal(t, Of(
+		Pair[int, string]{1, "x"}, Pair[int, string]{1, "y"},
+		Pair[int, string]{2, "x"}, Pair[int, string]{2, "y"},
+	), product)
+}
+
+func TestSet_Partition(t *testing.T) {
+	even, odd := Of(1, 2, 3, 4, 5).Partition(isEven)
+
+	assert.Equal(t, Of(2, 4), even)
+	assert.Equal(t, Of(1, 3, 5), odd)
+}
+
+func TestSet_Filter_Any_All(t *testing.T) {
+	s := Of(1, 2, 3, 4)
+
+	assert.Equal(t, Of(2, 4), s.Filter(isEven))
+	assert.True(t, s.Any(isEven))
+	assert.False(t, s.All(isEven))
+	assert.True(t, Of(2, 4).All(isEven))
+}
+
+func TestMap_and_Reduce(t *testing.T) {
+	lengths := Map(Of("one", "two", "three"), func(s string) int { return len(s) })
+
+	assert.Equal(t, Of(3, 5), lengths)
+	assert.Equal(t, 8, Reduce(0, lengths, func(sum, n int) int { return sum + n }))
+}
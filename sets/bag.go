@@ -0,0 +1,235 @@
+package sets
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Bag is a multiset: a set that counts how many times each element was added
+type Bag[T comparable] map[T]int
+
+// BagEntry is an element of a bag with its count
+type BagEntry[T comparable] struct {
+	Value T
+	Count int
+}
+
+// NewBag creates a new empty bag.
+func NewBag[T comparable]() Bag[T] {
+	return make(Bag[T])
+}
+
+// BagOf creates a new bag with the given elements, counting repeated ones.
+func BagOf[T comparable](ts ...T) Bag[T] {
+	b := make(Bag[T])
+	b.AddAll(ts...)
+	return b
+}
+
+// Add adds one occurrence of the given element to the bag.
+func (b Bag[T]) Add(v T) {
+	b.AddN(v, 1)
+}
+
+// AddN adds n occurrences of the given element to the bag. If n is negative, occurrences are removed.
+func (b Bag[T]) AddN(v T, n int) {
+	b.set(v, b[v]+n)
+}
+
+// AddAll adds one occurrence of each of the given elements to the bag.
+func (b Bag[T]) AddAll(v ...T) {
+	for _, v := range v {
+		b.Add(v)
+	}
+}
+
+// Remove removes one occurrence of the given element from the bag.
+func (b Bag[T]) Remove(v T) {
+	b.RemoveN(v, 1)
+}
+
+// RemoveN removes up to n occurrences of the given element from the bag.
+func (b Bag[T]) RemoveN(v T, n int) {
+	b.AddN(v, -n)
+}
+
+// Count returns the number of occurrences of the given element.
+func (b Bag[T]) Count(v T) int {
+	return b[v]
+}
+
+// Contains returns true if the bag has at least one occurrence of the given element.
+func (b Bag[T]) Contains(v T) bool {
+	return b[v] > 0
+}
+
+// Distinct returns the set of elements in the bag.
+func (b Bag[T]) Distinct() Set[T] {
+	distinct := make(Set[T], len(b))
+	for v := range b {
+		distinct.Add(v)
+	}
+	return distinct
+}
+
+// Size returns the number of distinct elements in the bag.
+func (b Bag[T]) Size() int {
+	return len(b)
+}
+
+// TotalSize returns the number of occurrences of all the elements in the bag.
+func (b Bag[T]) TotalSize() int {
+	total := 0
+	for _, count := range b {
+		total += count
+	}
+	return total
+}
+
+// IsEmpty returns true if the bag has no elements.
+func (b Bag[T]) IsEmpty() bool {
+	return len(b) == 0
+}
+
+// Entries returns the elements of the bag with their counts.
+func (b Bag[T]) Entries() []BagEntry[T] {
+	entries := make([]BagEntry[T], 0, len(b))
+	for v, count := range b {
+		entries = append(entries, BagEntry[T]{v, count})
+	}
+	return entries
+}
+
+// MostCommon returns the k elements with more occurrences, from the most common to the least one.
+// Elements with the same count are returned in no particular order. A negative k is treated as zero.
+func (b Bag[T]) MostCommon(k int) []BagEntry[T] {
+	if k < 0 {
+		k = 0
+	}
+
+	entries := b.Entries()
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Count > entries[j].Count })
+
+	if k < len(entries) {
+		entries = entries[:k]
+	}
+	return entries
+}
+
+// Union returns a new bag where each element has the maximum of its counts in this bag and the other one.
+func (b Bag[T]) Union(other Bag[T]) Bag[T] {
+	union := b.Copy()
+	for v, count := range other {
+		if count > union[v] {
+			union[v] = count
+		}
+	}
+	return union
+}
+
+// Intersection returns a new bag where each element has the minimum of its counts in this bag and the other one.
+func (b Bag[T]) Intersection(other Bag[T]) Bag[T] {
+	intersection := NewBag[T]()
+	for v, count := range b {
+		if otherCount := other[v]; otherCount < count {
+			intersection.set(v, otherCount)
+		} else {
+			intersection.set(v, count)
+		}
+	}
+	return intersection
+}
+
+// Sum returns a new bag where each element has the sum of its counts in this bag and the other one.
+func (b Bag[T]) Sum(other Bag[T]) Bag[T] {
+	sum := b.Copy()
+	for v, count := range other {
+		sum.AddN(v, count)
+	}
+	return sum
+}
+
+// Difference returns a new bag where each element has its count in this bag minus its count in the other one.
+func (b Bag[T]) Difference(other Bag[T]) Bag[T] {
+	difference := b.Copy()
+	for v, count := range other {
+		difference.RemoveN(v, count)
+	}
+	return difference
+}
+
+// Clear removes all elements from the bag.
+func (b Bag[T]) Clear() {
+	for v := range b {
+		delete(b, v)
+	}
+}
+
+// Copy returns a copy of the bag.
+func (b Bag[T]) Copy() Bag[T] {
+	bagCopy := make(Bag[T], len(b))
+	for v, count := range b {
+		bagCopy[v] = count
+	}
+	return bagCopy
+}
+
+// Equal returns true if both bags have the same elements with the same counts.
+func (b Bag[T]) Equal(other Bag[T]) bool {
+	if len(b) != len(other) {
+		return false
+	}
+	for v, count := range b {
+		if other[v] != count {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns a string representation of the bag.
+func (b Bag[T]) String() string {
+	str := "{"
+	for v, count := range b {
+		str += fmt.Sprintf("%v:%d ", v, count)
+	}
+	str += "}"
+	return str
+}
+
+// MarshalJSON writes the bag as an object with the counts of each element.
+func (b Bag[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[T]int(b))
+}
+
+// UnmarshalJSON reads the bag from an object with the counts of each element. Negative counts are rejected.
+func (b *Bag[T]) UnmarshalJSON(data []byte) error {
+	var counts map[T]int
+
+	if err := json.Unmarshal(data, &counts); err != nil {
+		return err
+	}
+
+	bag := make(Bag[T], len(counts))
+	for v, count := range counts {
+		if count < 0 {
+			return fmt.Errorf("negative count %d for %v", count, v)
+		}
+		bag.set(v, count)
+	}
+
+	*b = bag
+
+	return nil
+}
+
+// set keeps only elements with positive counts
+func (b Bag[T]) set(v T, count int) {
+	if count > 0 {
+		b[v] = count
+	} else {
+		delete(b, v)
+	}
+}
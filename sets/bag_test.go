@@ -0,0 +1,61 @@
+package sets
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBag_counts_occurrences(t *testing.T) {
+	b := BagOf("error", "ok", "error", "timeout", "error")
+	b.AddN("ok", 2)
+	b.Remove("timeout")
+
+	assert.Equal(t, 3, b.Count("error"))
+	assert.Equal(t, 3, b.Count("ok"))
+	assert.Equal(t, 0, b.Count("timeout"))
+	assert.False(t, b.Contains("timeout"))
+	assert.Equal(t, 6, b.TotalSize())
+	assert.Equal(t, Of("error", "ok"), b.Distinct())
+}
+
+func TestBag_RemoveN_never_goes_negative(t *testing.T) {
+	b := BagOf(1, 1)
+
+	b.RemoveN(1, 5)
+
+	assert.True(t, b.IsEmpty())
+}
+
+func TestBag_MostCommon(t *testing.T) {
+	b := BagOf("a", "b", "b", "c", "c", "c")
+
+	assert.Equal(t, []BagEntry[string]{{"c", 3}, {"b", 2}}, b.MostCommon(2))
+	assert.Len(t, b.MostCommon(10), 3)
+	assert.Empty(t, b.MostCommon(0))
+	assert.Empty(t, b.MostCommon(-1))
+}
+
+func TestBag_Union_Intersection_Sum_Difference(t *testing.T) {
+	a := BagOf("x", "x", "y")
+	b := BagOf("x", "y", "y", "z")
+
+	assert.Equal(t, Bag[string]{"x": 2, "y": 2, "z": 1}, a.Union(b))
+	assert.Equal(t, Bag[string]{"x": 1, "y": 1}, a.Intersection(b))
+	assert.Equal(t, Bag[string]{"x": 3, "y": 3, "z": 1}, a.Sum(b))
+	assert.Equal(t, Bag[string]{"x": 1}, a.Difference(b))
+}
+
+func TestBag_json(t *testing.T) {
+	data, err := json.Marshal(BagOf("a", "b", "b"))
+
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"a":1,"b":2}`, string(data))
+
+	var b Bag[string]
+	assert.Nil(t, json.Unmarshal([]byte(`{"a":1,"b":2,"c":0}`), &b))
+	assert.Equal(t, BagOf("a", "b", "b"), b)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"a":-1}`), &b))
+}
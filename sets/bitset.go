@@ -0,0 +1,341 @@
+package sets
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+	"strings"
+
+	"github.com/totemcaf/gollections/internal/binenc"
+)
+
+const wordSize = 64
+
+var errInvalidBitSet = errors.New("invalid bit set encoding")
+
+// BitSet is a set of non-negative integers stored as bits of machine words. It is much smaller and faster than
+// Set[int] when the elements are small and dense, like IDs, flags or shard numbers.
+// The zero value is an empty set ready to use.
+type BitSet struct {
+	words []uint64
+}
+
+// NewBitSet creates a new empty bit set.
+func NewBitSet() *BitSet {
+	return &BitSet{}
+}
+
+// BitSetOf creates a new bit set with the given elements.
+func BitSetOf(vs ...int) *BitSet {
+	b := NewBitSet()
+	b.AddAll(vs...)
+	return b
+}
+
+// Add adds the given element to the set. It panics if the element is negative.
+func (b *BitSet) Add(v int) {
+	if v < 0 {
+		panic(fmt.Sprintf("bit set element cannot be negative: %d", v))
+	}
+	word := v / wordSize
+	if word >= len(b.words) {
+		// append grows the capacity geometrically, so adding ascending elements copies the words a few times only
+		b.words = append(b.words, make([]uint64, word+1-len(b.words))...)
+	}
+	b.words[word] |= 1 << (v % wordSize)
+}
+
+// AddAll adds the given elements to the set.
+func (b *BitSet) AddAll(vs ...int) {
+	for _, v := range vs {
+		b.Add(v)
+	}
+}
+
+// Remove removes the given element from the set.
+func (b *BitSet) Remove(v int) {
+	if v < 0 || v/wordSize >= len(b.words) {
+		return
+	}
+	b.words[v/wordSize] &^= 1 << (v % wordSize)
+	b.trim()
+}
+
+// Contains returns true if the set contains the given element.
+func (b *BitSet) Contains(v int) bool {
+	if v < 0 || v/wordSize >= len(b.words) {
+		return false
+	}
+	return b.words[v/wordSize]&(1<<(v%wordSize)) != 0
+}
+
+// Cardinality returns the number of elements in the set.
+func (b *BitSet) Cardinality() int {
+	count := 0
+	for _, w := range b.words {
+		count += bits.OnesCount64(w)
+	}
+	return count
+}
+
+// Size returns the number of elements in the set, it is the same as Cardinality.
+func (b *BitSet) Size() int {
+	return b.Cardinality()
+}
+
+// IsEmpty returns true if the set is empty. It has no elements.
+func (b *BitSet) IsEmpty() bool {
+	return len(b.words) == 0
+}
+
+// NextSet returns the smallest element that is greater than or equal to from. It returns false if there is none.
+func (b *BitSet) NextSet(from int) (int, bool) {
+	if from < 0 {
+		from = 0
+	}
+	word := from / wordSize
+	if word >= len(b.words) {
+		return 0, false
+	}
+
+	w := b.words[word] >> (from % wordSize)
+	if w != 0 {
+		return from + bits.TrailingZeros64(w), true
+	}
+
+	for word++; word < len(b.words); word++ {
+		if b.words[word] != 0 {
+			return word*wordSize + bits.TrailingZeros64(b.words[word]), true
+		}
+	}
+
+	return 0, false
+}
+
+// PrevSet returns the greatest element that is less than or equal to from. It returns false if there is none.
+func (b *BitSet) PrevSet(from int) (int, bool) {
+	if from < 0 || len(b.words) == 0 {
+		return 0, false
+	}
+	word := from / wordSize
+	if word >= len(b.words) {
+		word = len(b.words) - 1
+		from = word*wordSize + wordSize - 1
+	}
+
+	w := b.words[word] << (wordSize - 1 - from%wordSize)
+	if w != 0 {
+		return from - bits.LeadingZeros64(w), true
+	}
+
+	for word--; word >= 0; word-- {
+		if b.words[word] != 0 {
+			return word*wordSize + wordSize - 1 - bits.LeadingZeros64(b.words[word]), true
+		}
+	}
+
+	return 0, false
+}
+
+// Range calls fn with each element of the set, in ascending order. Iteration stops if fn returns false.
+func (b *BitSet) Range(fn func(int) bool) {
+	for word, w := range b.words {
+		for w != 0 {
+			bit := bits.TrailingZeros64(w)
+			if !fn(word*wordSize + bit) {
+				return
+			}
+			w &^= 1 << bit
+		}
+	}
+}
+
+// Values returns the elements of the set as a slice, in ascending order.
+func (b *BitSet) Values() []int {
+	values := make([]int, 0, b.Cardinality())
+	b.Range(func(v int) bool {
+		values = append(values, v)
+		return true
+	})
+	return values
+}
+
+// Union returns a new set with all the elements of the set and the given set.
+func (b *BitSet) Union(other *BitSet) *BitSet {
+	long, short := b.words, other.words
+	if len(long) < len(short) {
+		long, short = short, long
+	}
+
+	words := make([]uint64, len(long))
+	copy(words, long)
+	for idx, w := range short {
+		words[idx] |= w
+	}
+
+	return &BitSet{words}
+}
+
+// Intersection returns a new set with the elements that are in both this set and the other set.
+func (b *BitSet) Intersection(other *BitSet) *BitSet {
+	size := len(b.words)
+	if len(other.words) < size {
+		size = len(other.words)
+	}
+
+	words := make([]uint64, size)
+	for idx := range words {
+		words[idx] = b.words[idx] & other.words[idx]
+	}
+
+	result := &BitSet{words}
+	result.trim()
+	return result
+}
+
+// Difference returns a new set with the elements that are in this set but not in the other.
+func (b *BitSet) Difference(other *BitSet) *BitSet {
+	words := make([]uint64, len(b.words))
+	copy(words, b.words)
+	for idx := 0; idx < len(words) && idx < len(other.words); idx++ {
+		words[idx] &^= other.words[idx]
+	}
+
+	result := &BitSet{words}
+	result.trim()
+	return result
+}
+
+// SymmetricDifference returns a new set with the elements that are in this set or the other set but not in both.
+func (b *BitSet) SymmetricDifference(other *BitSet) *BitSet {
+	long, short := b.words, other.words
+	if len(long) < len(short) {
+		long, short = short, long
+	}
+
+	words := make([]uint64, len(long))
+	copy(words, long)
+	for idx, w := range short {
+		words[idx] ^= w
+	}
+
+	result := &BitSet{words}
+	result.trim()
+	return result
+}
+
+// IsSubset returns true if all elements of this set are also in the other set.
+func (b *BitSet) IsSubset(other *BitSet) bool {
+	if len(b.words) > len(other.words) {
+		return false
+	}
+	for idx, w := range b.words {
+		if w&^other.words[idx] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset returns true if all elements of the other set are also in this set.
+func (b *BitSet) IsSuperset(other *BitSet) bool {
+	return other.IsSubset(b)
+}
+
+// IsDisjoint returns true if the sets have no elements in common.
+func (b *BitSet) IsDisjoint(other *BitSet) bool {
+	for idx := 0; idx < len(b.words) && idx < len(other.words); idx++ {
+		if b.words[idx]&other.words[idx] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal returns true if the set is equal to the other set. Two sets are equal if they have the same elements.
+func (b *BitSet) Equal(other *BitSet) bool {
+	if len(b.words) != len(other.words) {
+		return false
+	}
+	for idx, w := range b.words {
+		if w != other.words[idx] {
+			return false
+		}
+	}
+	return true
+}
+
+// Clear removes all elements from the set.
+func (b *BitSet) Clear() {
+	b.words = nil
+}
+
+// Copy returns a copy of the set.
+func (b *BitSet) Copy() *BitSet {
+	words := make([]uint64, len(b.words))
+	copy(words, b.words)
+	return &BitSet{words}
+}
+
+// ToSet returns a Set with the elements of the bit set.
+func (b *BitSet) ToSet() Set[int] {
+	return Of(b.Values()...)
+}
+
+// String returns a string representation of the set, with the elements in ascending order.
+func (b *BitSet) String() string {
+	var builder strings.Builder
+	builder.WriteString("{")
+	b.Range(func(v int) bool {
+		fmt.Fprintf(&builder, "%d ", v)
+		return true
+	})
+	builder.WriteString("}")
+	return builder.String()
+}
+
+// MarshalBinary encodes the set as the number of words, as a varint, followed by the words in little endian.
+func (b *BitSet) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, binary.MaxVarintLen64+len(b.words)*8)
+	data = binenc.AppendUvarint(data, uint64(len(b.words)))
+
+	for _, w := range b.words {
+		data = binenc.AppendUint64(data, w)
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary decodes a set written by MarshalBinary.
+func (b *BitSet) UnmarshalBinary(data []byte) error {
+	reader := binenc.NewReader(data, errInvalidBitSet)
+
+	count := reader.Uvarint()
+	if count > uint64(reader.Remaining()/8) {
+		return errInvalidBitSet
+	}
+
+	words := make([]uint64, count)
+	for idx := range words {
+		words[idx] = reader.Uint64()
+	}
+
+	if err := reader.Finish(); err != nil {
+		return err
+	}
+
+	b.words = words
+	b.trim()
+
+	return nil
+}
+
+// trim removes the empty words at the end, so the length of the words is the same for equal sets
+func (b *BitSet) trim() {
+	last := len(b.words)
+	for last > 0 && b.words[last-1] == 0 {
+		last--
+	}
+	b.words = b.words[:last]
+}
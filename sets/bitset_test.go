@@ -0,0 +1,87 @@
+package sets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBitSet_Add_Remove_Contains(t *testing.T) {
+	b := BitSetOf(1, 64, 200)
+	b.Remove(64)
+	b.Remove(1000)
+
+	assert.True(t, b.Contains(1))
+	assert.False(t, b.Contains(64))
+	assert.True(t, b.Contains(200))
+	assert.False(t, b.Contains(-1))
+	assert.Equal(t, 2, b.Cardinality())
+	assert.Equal(t, []int{1, 200}, b.Values())
+	assert.Panics(t, func() { b.Add(-1) })
+}
+
+func TestBitSet_zero_value_is_empty_set(t *testing.T) {
+	var b BitSet
+
+	assert.True(t, b.IsEmpty())
+	b.Add(3)
+	assert.Equal(t, "{3 }", b.String())
+}
+
+func TestBitSet_algebra(t *testing.T) {
+	a := BitSetOf(1, 2, 3, 130)
+	b := BitSetOf(3, 4)
+
+	assert.Equal(t, []int{1, 2, 3, 4, 130}, a.Union(b).Values())
+	assert.Equal(t, []int{3}, a.Intersection(b).Values())
+	assert.Equal(t, []int{1, 2, 130}, a.Difference(b).Values())
+	assert.Equal(t, []int{1, 2, 4, 130}, a.SymmetricDifference(b).Values())
+	assert.True(t, BitSetOf(1, 130).IsSubset(a))
+	assert.False(t, a.IsSubset(BitSetOf(1, 130)))
+	assert.True(t, a.IsSuperset(BitSetOf(2)))
+	assert.True(t, a.IsDisjoint(BitSetOf(5, 500)))
+	assert.True(t, a.Difference(BitSetOf(130)).Equal(BitSetOf(1, 2, 3)))
+}
+
+func TestBitSet_NextSet_PrevSet(t *testing.T) {
+	b := BitSetOf(5, 63, 64, 300)
+
+	var forward []int
+	for v, ok := b.NextSet(0); ok; v, ok = b.NextSet(v + 1) {
+		forward = append(forward, v)
+	}
+	var backward []int
+	for v, ok := b.PrevSet(1000); ok; v, ok = b.PrevSet(v - 1) {
+		backward = append(backward, v)
+	}
+
+	assert.Equal(t, []int{5, 63, 64, 300}, forward)
+	assert.Equal(t, []int{300, 64, 63, 5}, backward)
+	_, found := b.PrevSet(4)
+	assert.False(t, found)
+}
+
+func TestBitSet_binary_encoding(t *testing.T) {
+	b := BitSetOf(0, 7, 64, 1000)
+
+	data, err := b.MarshalBinary()
+	assert.Nil(t, err)
+	assert.Len(t, data, 1+16*8)
+
+	var read BitSet
+	assert.Nil(t, read.UnmarshalBinary(data))
+	assert.True(t, b.Equal(&read))
+
+	assert.Error(t, read.UnmarshalBinary(data[:10]))
+}
+
+func TestBitSet_Add_grows_amortized(t *testing.T) {
+	allocs := testing.AllocsPerRun(1, func() {
+		b := NewBitSet()
+		for v := 0; v < 1000*wordSize; v += wordSize {
+			b.Add(v)
+		}
+	})
+
+	assert.Less(t, allocs, 50.0)
+}
@@ -0,0 +1,158 @@
+package sets
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// SortedValues returns the elements of the set as a slice. If the elements are numbers or strings (or types
+// based on them) they are sorted, otherwise they are in no particular order.
+func (s Set[T]) SortedValues() []T {
+	values := s.Values()
+
+	if less := lessOf(reflect.TypeOf(values).Elem()); less != nil {
+		sort.Slice(values, func(i, j int) bool {
+			return less(reflect.ValueOf(values[i]), reflect.ValueOf(values[j]))
+		})
+	}
+
+	return values
+}
+
+func lessOf(t reflect.Type) func(a, b reflect.Value) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(a, b reflect.Value) bool { return a.Int() < b.Int() }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(a, b reflect.Value) bool { return a.Uint() < b.Uint() }
+	case reflect.Float32, reflect.Float64:
+		return func(a, b reflect.Value) bool { return a.Float() < b.Float() }
+	case reflect.String:
+		return func(a, b reflect.Value) bool { return a.String() < b.String() }
+	default:
+		return nil
+	}
+}
+
+// MarshalJSON writes the set as an array, sorted as in SortedValues.
+func (s Set[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.SortedValues())
+}
+
+// UnmarshalJSON reads the set from an array. Repeated elements are added once, use Strict to reject them.
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	var values []T
+
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	*s = Of(values...)
+
+	return nil
+}
+
+// MarshalYAML writes the set as a sequence, sorted as in SortedValues.
+func (s Set[T]) MarshalYAML() (interface{}, error) {
+	return s.SortedValues(), nil
+}
+
+// UnmarshalYAML reads the set from a sequence. Repeated elements are added once, use Strict to reject them.
+// It uses the unmarshal function form, so the package does not depend on a YAML library.
+func (s *Set[T]) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var values []T
+
+	if err := unmarshal(&values); err != nil {
+		return err
+	}
+
+	*s = Of(values...)
+
+	return nil
+}
+
+// Value stores the set in a database column as a JSON array.
+func (s Set[T]) Value() (driver.Value, error) {
+	data, err := s.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan reads the set from a database column with a JSON array. NULL is read as an empty set.
+func (s *Set[T]) Scan(src any) error {
+	switch data := src.(type) {
+	case nil:
+		*s = New[T]()
+		return nil
+	case []byte:
+		return s.UnmarshalJSON(data)
+	case string:
+		return s.UnmarshalJSON([]byte(data))
+	default:
+		return fmt.Errorf("cannot scan %T into a set", src)
+	}
+}
+
+// Strict is a set that rejects repeated elements when it is read from JSON, YAML or a database column.
+// It can be used in configuration and DTOs where a repeated value is a mistake.
+type Strict[T comparable] struct {
+	Set[T]
+}
+
+// UnmarshalJSON reads the set from an array, failing if an element is repeated.
+func (s *Strict[T]) UnmarshalJSON(data []byte) error {
+	var values []T
+
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	return s.fill(values)
+}
+
+// UnmarshalYAML reads the set from a sequence, failing if an element is repeated.
+func (s *Strict[T]) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var values []T
+
+	if err := unmarshal(&values); err != nil {
+		return err
+	}
+
+	return s.fill(values)
+}
+
+// Scan reads the set from a database column with a JSON array, failing if an element is repeated.
+// NULL is read as an empty set.
+func (s *Strict[T]) Scan(src any) error {
+	switch data := src.(type) {
+	case nil:
+		s.Set = New[T]()
+		return nil
+	case []byte:
+		return s.UnmarshalJSON(data)
+	case string:
+		return s.UnmarshalJSON([]byte(data))
+	default:
+		return fmt.Errorf("cannot scan %T into a set", src)
+	}
+}
+
+func (s *Strict[T]) fill(values []T) error {
+	set := New[T]()
+
+	for _, v := range values {
+		if set.Contains(v) {
+			return fmt.Errorf("repeated element %v", v)
+		}
+		set.Add(v)
+	}
+
+	s.Set = set
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package sets
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type config struct {
+	Hosts   Set[string]    `json:"hosts" yaml:"hosts"`
+	Regions Strict[string] `json:"regions" yaml:"regions"`
+}
+
+func TestSet_MarshalJSON_is_sorted_array(t *testing.T) {
+	data, err := json.Marshal(Of(42, 3, 17))
+
+	assert.Nil(t, err)
+	assert.Equal(t, `[3,17,42]`, string(data))
+}
+
+func TestSet_UnmarshalJSON_from_array(t *testing.T) {
+	var s Set[string]
+
+	err := json.Unmarshal([]byte(`["b","a","b"]`), &s)
+
+	assert.Nil(t, err)
+	assert.Equal(t, Of("a", "b"), s)
+}
+
+func TestStrict_rejects_repeated_elements(t *testing.T) {
+	var c config
+
+	errJSON := json.Unmarshal([]byte(`{"hosts":["h1","h1"],"regions":["us","us"]}`), &c)
+	errYAML := yaml.Unmarshal([]byte("regions: [us, eu, us]"), &c)
+
+	assert.ErrorContains(t, errJSON, "repeated element us")
+	assert.ErrorContains(t, errYAML, "repeated element us")
+}
+
+func TestSet_yaml_round_trip(t *testing.T) {
+	c := config{Hosts: Of("h2", "h1"), Regions: Strict[string]{Of("us")}}
+
+	data, err := yaml.Marshal(c)
+	assert.Nil(t, err)
+	assert.Equal(t, "hosts:\n    - h1\n    - h2\nregions:\n    - us\n", string(data))
+
+	var read config
+	assert.Nil(t, yaml.Unmarshal(data, &read))
+	assert.Equal(t, c, read)
+}
+
+func TestSet_Value_and_Scan(t *testing.T) {
+	value, err := Of("b", "a").Value()
+	assert.Nil(t, err)
+	assert.Equal(t, `["a","b"]`, value)
+
+	var s Set[string]
+	assert.Nil(t, s.Scan([]byte(`["x","y"]`)))
+	assert.Equal(t, Of("x", "y"), s)
+
+	assert.Nil(t, s.Scan(nil))
+	assert.True(t, s.IsEmpty())
+
+	assert.Error(t, s.Scan(42))
+}
+
+func TestStrict_Scan_rejects_repeated_elements(t *testing.T) {
+	var s Strict[string]
+
+	assert.ErrorContains(t, s.Scan(`["x","x"]`), "repeated element x")
+	assert.Nil(t, s.Scan([]byte(`["x","y"]`)))
+	assert.Equal(t, Of("x", "y"), s.Set)
+	assert.Nil(t, s.Scan(nil))
+	assert.True(t, s.IsEmpty())
+}
@@ -0,0 +1,158 @@
+package sets
+
+import (
+	"fmt"
+
+	"github.com/totemcaf/gollections/hashes"
+	"github.com/totemcaf/gollections/internal/hamt"
+)
+
+// Persistent is an immutable set. Operations that change it return a new set, leaving the original untouched,
+// like lists.List does with Append. New versions share most of their structure, so updates are cheap.
+// The zero value is an empty set ready to use.
+type Persistent[T comparable] struct {
+	tree hamt.Map[T, struct{}]
+}
+
+// NewPersistent creates an empty persistent set.
+func NewPersistent[T comparable]() Persistent[T] {
+	return Persistent[T]{hamt.New[T, struct{}](hashes.For[T]())}
+}
+
+// PersistentOf creates a persistent set with the given elements.
+func PersistentOf[T comparable](ts ...T) Persistent[T] {
+	return NewPersistent[T]().WithAll(ts...)
+}
+
+func (p Persistent[T]) init() Persistent[T] {
+	if p.tree.IsZero() {
+		return NewPersistent[T]()
+	}
+	return p
+}
+
+// With returns a set with the elements of this set and the given one.
+func (p Persistent[T]) With(v T) Persistent[T] {
+	p = p.init()
+	return Persistent[T]{p.tree.Set(v, struct{}{})}
+}
+
+// WithAll returns a set with the elements of this set and the given ones.
+func (p Persistent[T]) WithAll(ts ...T) Persistent[T] {
+	p = p.init()
+	for _, v := range ts {
+		p.tree = p.tree.Set(v, struct{}{})
+	}
+	return p
+}
+
+// Without returns a set with the elements of this set except the given one.
+func (p Persistent[T]) Without(v T) Persistent[T] {
+	if p.tree.IsZero() {
+		return p
+	}
+	return Persistent[T]{p.tree.Delete(v)}
+}
+
+// Contains returns true if the set contains the given element.
+func (p Persistent[T]) Contains(v T) bool {
+	if p.tree.IsZero() {
+		return false
+	}
+	_, found := p.tree.Get(v)
+	return found
+}
+
+// Size returns the number of elements in the set.
+func (p Persistent[T]) Size() int {
+	return p.tree.Len()
+}
+
+// IsEmpty returns true if the set has no elements.
+func (p Persistent[T]) IsEmpty() bool {
+	return p.Size() == 0
+}
+
+// Range calls fn with each element of the set, in no particular order. Iteration stops if fn returns false.
+func (p Persistent[T]) Range(fn func(T) bool) {
+	if p.tree.IsZero() {
+		return
+	}
+	p.tree.Range(func(v T, _ struct{}) bool {
+		return fn(v)
+	})
+}
+
+// Values returns the elements of the set as a slice.
+func (p Persistent[T]) Values() []T {
+	values := make([]T, 0, p.Size())
+	p.Range(func(v T) bool {
+		values = append(values, v)
+		return true
+	})
+	return values
+}
+
+// Union returns a set with the elements of this set and the other one.
+func (p Persistent[T]) Union(other Persistent[T]) Persistent[T] {
+	if p.Size() < other.Size() {
+		p, other = other, p
+	}
+	union := p.init()
+	other.Range(func(v T) bool {
+		union.tree = union.tree.Set(v, struct{}{})
+		return true
+	})
+	return union
+}
+
+// Intersection returns a set with the elements that are in both this set and the other one.
+func (p Persistent[T]) Intersection(other Persistent[T]) Persistent[T] {
+	intersection := NewPersistent[T]()
+	p.Range(func(v T) bool {
+		if other.Contains(v) {
+			intersection.tree = intersection.tree.Set(v, struct{}{})
+		}
+		return true
+	})
+	return intersection
+}
+
+// Difference returns a set with the elements of this set that are not in the other one.
+func (p Persistent[T]) Difference(other Persistent[T]) Persistent[T] {
+	difference := p
+	other.Range(func(v T) bool {
+		difference = difference.Without(v)
+		return true
+	})
+	return difference
+}
+
+// Equal returns true if both sets have the same elements.
+func (p Persistent[T]) Equal(other Persistent[T]) bool {
+	if p.Size() != other.Size() {
+		return false
+	}
+	equal := true
+	p.Range(func(v T) bool {
+		equal = other.Contains(v)
+		return equal
+	})
+	return equal
+}
+
+// ToSet returns a new mutable set with the elements of the set.
+func (p Persistent[T]) ToSet() Set[T] {
+	return Of(p.Values()...)
+}
+
+// String returns a string representation of the set.
+func (p Persistent[T]) String() string {
+	str := "{"
+	p.Range(func(v T) bool {
+		str += fmt.Sprintf("%v ", v)
+		return true
+	})
+	str += "}"
+	return str
+}
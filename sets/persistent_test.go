@@ -0,0 +1,52 @@
+package sets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPersistent_updates_return_new_sets(t *testing.T) {
+	original := PersistentOf(1, 2, 3)
+
+	added := original.With(4)
+	removed := original.Without(1)
+
+	assert.Equal(t, Of(1, 2, 3), original.ToSet())
+	assert.Equal(t, Of(1, 2, 3, 4), added.ToSet())
+	assert.Equal(t, Of(2, 3), removed.ToSet())
+}
+
+func TestPersistent_zero_value_is_empty_set(t *testing.T) {
+	var empty Persistent[string]
+
+	assert.True(t, empty.IsEmpty())
+	assert.False(t, empty.Contains("a"))
+	assert.True(t, empty.With("a").Contains("a"))
+	assert.True(t, empty.Without("a").IsEmpty())
+}
+
+func TestPersistent_algebra(t *testing.T) {
+	a := PersistentOf("x", "y")
+	b := PersistentOf("y", "z")
+
+	assert.Equal(t, Of("x", "y", "z"), a.Union(b).ToSet())
+	assert.Equal(t, Of("y"), a.Intersection(b).ToSet())
+	assert.Equal(t, Of("x"), a.Difference(b).ToSet())
+	assert.True(t, a.Equal(PersistentOf("y", "x")))
+	assert.False(t, a.Equal(b))
+}
+
+func TestPersistent_many_elements(t *testing.T) {
+	var s Persistent[int]
+	for i := 0; i < 10000; i++ {
+		s = s.With(i)
+	}
+	for i := 0; i < 10000; i += 2 {
+		s = s.Without(i)
+	}
+
+	assert.Equal(t, 5000, s.Size())
+	assert.True(t, s.Contains(9999))
+	assert.False(t, s.Contains(9998))
+}
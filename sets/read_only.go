@@ -0,0 +1,67 @@
+package sets
+
+// ReadOnly is a view of a Set that only exposes queries. It can be given to code that must not modify the set.
+// The view is not a copy: changes made through the original set are visible.
+type ReadOnly[T comparable] struct {
+	set Set[T]
+}
+
+// Contains returns true if the set contains the given element.
+func (r ReadOnly[T]) Contains(v T) bool {
+	return r.set.Contains(v)
+}
+
+// Size returns the number of elements in the set.
+func (r ReadOnly[T]) Size() int {
+	return r.set.Size()
+}
+
+// IsEmpty returns true if the set has no elements.
+func (r ReadOnly[T]) IsEmpty() bool {
+	return r.set.IsEmpty()
+}
+
+// Values returns the elements of the set as a slice.
+func (r ReadOnly[T]) Values() []T {
+	return r.set.Values()
+}
+
+// SortedValues returns the elements of the set as a slice, sorted as in Set.SortedValues.
+func (r ReadOnly[T]) SortedValues() []T {
+	return r.set.SortedValues()
+}
+
+// Range calls fn with each element of the set. Iteration stops if fn returns false.
+func (r ReadOnly[T]) Range(fn func(T) bool) {
+	r.set.Range(fn)
+}
+
+// IsSubset returns true if all the elements of the set are in the other set.
+func (r ReadOnly[T]) IsSubset(other Set[T]) bool {
+	return r.set.IsSubset(other)
+}
+
+// IsSuperset returns true if all the elements of the other set are in the set.
+func (r ReadOnly[T]) IsSuperset(other Set[T]) bool {
+	return r.set.IsSuperset(other)
+}
+
+// Equal returns true if the set has the same elements than the other set.
+func (r ReadOnly[T]) Equal(other Set[T]) bool {
+	return r.set.Equal(other)
+}
+
+// Copy returns a new mutable set with the elements of the set.
+func (r ReadOnly[T]) Copy() Set[T] {
+	return r.set.Copy()
+}
+
+// String returns a string representation of the set.
+func (r ReadOnly[T]) String() string {
+	return r.set.String()
+}
+
+// MarshalJSON writes the set as an array, see Set.MarshalJSON.
+func (r ReadOnly[T]) MarshalJSON() ([]byte, error) {
+	return r.set.MarshalJSON()
+}
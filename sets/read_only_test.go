@@ -0,0 +1,27 @@
+package sets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadOnly_sees_changes_of_the_set(t *testing.T) {
+	s := Of(1, 2)
+	view := s.ReadOnly()
+
+	s.Add(3)
+
+	assert.True(t, view.Contains(3))
+	assert.Equal(t, 3, view.Size())
+	assert.Equal(t, []int{1, 2, 3}, view.SortedValues())
+}
+
+func TestReadOnly_Copy_is_independent(t *testing.T) {
+	s := Of("a")
+
+	copied := s.ReadOnly().Copy()
+	copied.Add("b")
+
+	assert.Equal(t, Of("a"), s)
+}
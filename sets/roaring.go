@@ -0,0 +1,446 @@
+package sets
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/totemcaf/gollections/internal/binenc"
+)
+
+const (
+	roaringFormatVersion = 1
+
+	arrayContainerType  = 0
+	bitmapContainerType = 1
+	runContainerType    = 2
+)
+
+var errInvalidRoaring = errors.New("invalid roaring bitmap encoding")
+
+// Roaring is a compressed set of uint32, like a roaring bitmap. Elements are grouped by their high 16 bits and each
+// group is kept in the smallest of three containers: a sorted array for sparse groups, a bitmap for dense ones or
+// a list of runs for groups of consecutive elements. It uses a small fraction of the memory of Set[uint32] for
+// large sets of IDs. The zero value is an empty set ready to use.
+type Roaring struct {
+	keys       []uint16
+	containers []container
+}
+
+// NewRoaring creates a new empty roaring bitmap.
+func NewRoaring() *Roaring {
+	return &Roaring{}
+}
+
+// RoaringOf creates a new roaring bitmap with the given elements.
+func RoaringOf(vs ...uint32) *Roaring {
+	r := NewRoaring()
+	r.AddAll(vs...)
+	return r
+}
+
+func split(v uint32) (uint16, uint16) {
+	return uint16(v >> 16), uint16(v)
+}
+
+func join(high, low uint16) uint32 {
+	return uint32(high)<<16 | uint32(low)
+}
+
+// search returns the position of the container for the high bits and if it exists
+func (r *Roaring) search(high uint16) (int, bool) {
+	idx := sort.Search(len(r.keys), func(i int) bool { return r.keys[i] >= high })
+	return idx, idx < len(r.keys) && r.keys[idx] == high
+}
+
+func (r *Roaring) insert(idx int, high uint16, c container) {
+	r.keys = append(r.keys, 0)
+	copy(r.keys[idx+1:], r.keys[idx:])
+	r.keys[idx] = high
+
+	r.containers = append(r.containers, nil)
+	copy(r.containers[idx+1:], r.containers[idx:])
+	r.containers[idx] = c
+}
+
+func (r *Roaring) delete(idx int) {
+	r.keys = append(r.keys[:idx], r.keys[idx+1:]...)
+	r.containers = append(r.containers[:idx], r.containers[idx+1:]...)
+}
+
+// Add adds the given element to the set.
+func (r *Roaring) Add(v uint32) {
+	high, low := split(v)
+	idx, found := r.search(high)
+	if found {
+		r.containers[idx] = r.containers[idx].add(low)
+		return
+	}
+	r.insert(idx, high, &arrayContainer{[]uint16{low}})
+}
+
+// AddAll adds the given elements to the set.
+func (r *Roaring) AddAll(vs ...uint32) {
+	for _, v := range vs {
+		r.Add(v)
+	}
+}
+
+// AddRange adds all the elements from start to last, both included.
+func (r *Roaring) AddRange(start, last uint32) {
+	for from := uint64(start); from <= uint64(last); {
+		high, low := split(uint32(from))
+		to := uint64(join(high, 0xFFFF))
+		if to > uint64(last) {
+			to = uint64(last)
+		}
+
+		run := &runContainer{[]interval{{low, uint16(to)}}}
+		if idx, found := r.search(high); found {
+			r.containers[idx] = unionContainers(r.containers[idx], run)
+		} else {
+			r.insert(idx, high, run)
+		}
+
+		from = to + 1
+	}
+}
+
+// Remove removes the given element from the set.
+func (r *Roaring) Remove(v uint32) {
+	high, low := split(v)
+	idx, found := r.search(high)
+	if !found {
+		return
+	}
+	r.containers[idx] = r.containers[idx].remove(low)
+	if r.containers[idx].cardinality() == 0 {
+		r.delete(idx)
+	}
+}
+
+// Contains returns true if the set contains the given element.
+func (r *Roaring) Contains(v uint32) bool {
+	high, low := split(v)
+	idx, found := r.search(high)
+	return found && r.containers[idx].contains(low)
+}
+
+// Cardinality returns the number of elements in the set.
+func (r *Roaring) Cardinality() int {
+	card := 0
+	for _, c := range r.containers {
+		card += c.cardinality()
+	}
+	return card
+}
+
+// Size returns the number of elements in the set, it is the same as Cardinality.
+func (r *Roaring) Size() int {
+	return r.Cardinality()
+}
+
+// IsEmpty returns true if the set is empty. It has no elements.
+func (r *Roaring) IsEmpty() bool {
+	return len(r.keys) == 0
+}
+
+// Rank returns the number of elements that are less than or equal to v.
+func (r *Roaring) Rank(v uint32) int {
+	high, low := split(v)
+	rank := 0
+	for idx, key := range r.keys {
+		if key > high {
+			break
+		}
+		if key == high {
+			return rank + r.containers[idx].rank(low)
+		}
+		rank += r.containers[idx].cardinality()
+	}
+	return rank
+}
+
+// Select returns the n-th element of the set in ascending order, starting at 0. It returns false if the set has
+// not so many elements.
+func (r *Roaring) Select(n int) (uint32, bool) {
+	if n < 0 {
+		return 0, false
+	}
+	for idx, c := range r.containers {
+		card := c.cardinality()
+		if n < card {
+			return join(r.keys[idx], c.selectAt(n)), true
+		}
+		n -= card
+	}
+	return 0, false
+}
+
+// Range calls fn with each element of the set, in ascending order. Iteration stops if fn returns false.
+func (r *Roaring) Range(fn func(uint32) bool) {
+	for idx, c := range r.containers {
+		high := r.keys[idx]
+		if !c.iterate(func(low uint16) bool { return fn(join(high, low)) }) {
+			return
+		}
+	}
+}
+
+// Values returns the elements of the set as a slice, in ascending order.
+func (r *Roaring) Values() []uint32 {
+	values := make([]uint32, 0, r.Cardinality())
+	r.Range(func(v uint32) bool {
+		values = append(values, v)
+		return true
+	})
+	return values
+}
+
+// Optimize changes each container to its smallest representation. It is done by the set operations, but it is
+// useful after adding many elements one by one.
+func (r *Roaring) Optimize() {
+	for idx, c := range r.containers {
+		r.containers[idx] = optimize(c)
+	}
+}
+
+// combine merges the containers of both sets. Containers with keys in only one of the sets are kept if requested,
+// and the ones with the same key are combined with the operation.
+func (r *Roaring) combine(
+	other *Roaring,
+	keepOnlyThis, keepOnlyOther bool,
+	operation func(a, b container) container,
+) *Roaring {
+	result := NewRoaring()
+	add := func(key uint16, c container) {
+		if c != nil {
+			result.keys = append(result.keys, key)
+			result.containers = append(result.containers, c)
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(r.keys) && j < len(other.keys) {
+		switch {
+		case r.keys[i] < other.keys[j]:
+			if keepOnlyThis {
+				add(r.keys[i], r.containers[i].clone())
+			}
+			i++
+		case r.keys[i] > other.keys[j]:
+			if keepOnlyOther {
+				add(other.keys[j], other.containers[j].clone())
+			}
+			j++
+		default:
+			add(r.keys[i], operation(r.containers[i], other.containers[j]))
+			i++
+			j++
+		}
+	}
+	for ; keepOnlyThis && i < len(r.keys); i++ {
+		add(r.keys[i], r.containers[i].clone())
+	}
+	for ; keepOnlyOther && j < len(other.keys); j++ {
+		add(other.keys[j], other.containers[j].clone())
+	}
+
+	return result
+}
+
+// Union returns a new set with all the elements of the set and the given set.
+func (r *Roaring) Union(other *Roaring) *Roaring {
+	return r.combine(other, true, true, unionContainers)
+}
+
+// Intersection returns a new set with the elements that are in both this set and the other set.
+func (r *Roaring) Intersection(other *Roaring) *Roaring {
+	return r.combine(other, false, false, intersectContainers)
+}
+
+// Difference returns a new set with the elements that are in this set but not in the other.
+func (r *Roaring) Difference(other *Roaring) *Roaring {
+	return r.combine(other, true, false, differenceContainers)
+}
+
+// SymmetricDifference returns a new set with the elements that are in this set or the other set but not in both.
+func (r *Roaring) SymmetricDifference(other *Roaring) *Roaring {
+	return r.combine(other, true, true, symmetricDifferenceContainers)
+}
+
+// IsSubset returns true if all elements of this set are also in the other set.
+func (r *Roaring) IsSubset(other *Roaring) bool {
+	for idx, key := range r.keys {
+		otherIdx, found := other.search(key)
+		if !found {
+			return false
+		}
+		c, otherContainer := r.containers[idx], other.containers[otherIdx]
+		if c.cardinality() > otherContainer.cardinality() {
+			return false
+		}
+		if !c.iterate(otherContainer.contains) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset returns true if all elements of the other set are also in this set.
+func (r *Roaring) IsSuperset(other *Roaring) bool {
+	return other.IsSubset(r)
+}
+
+// Equal returns true if the set is equal to the other set. Two sets are equal if they have the same elements.
+func (r *Roaring) Equal(other *Roaring) bool {
+	if len(r.keys) != len(other.keys) || r.Cardinality() != other.Cardinality() {
+		return false
+	}
+	return r.IsSubset(other)
+}
+
+// Clear removes all elements from the set.
+func (r *Roaring) Clear() {
+	r.keys = nil
+	r.containers = nil
+}
+
+// Copy returns a copy of the set.
+func (r *Roaring) Copy() *Roaring {
+	result := &Roaring{
+		keys:       make([]uint16, len(r.keys)),
+		containers: make([]container, len(r.containers)),
+	}
+	copy(result.keys, r.keys)
+	for idx, c := range r.containers {
+		result.containers[idx] = c.clone()
+	}
+	return result
+}
+
+// String returns a string representation of the set, with the elements in ascending order.
+func (r *Roaring) String() string {
+	var builder strings.Builder
+	builder.WriteString("{")
+	r.Range(func(v uint32) bool {
+		fmt.Fprintf(&builder, "%d ", v)
+		return true
+	})
+	builder.WriteString("}")
+	return builder.String()
+}
+
+// MarshalBinary encodes the set in a format that does not depend on the platform. It is a version byte and the
+// number of containers as a varint, then for each container its key, its type and its content. All the fixed size
+// numbers are little endian.
+//
+//   - array: the number of elements as a varint followed by the 16 bits elements
+//   - bitmap: 1024 words of 64 bits
+//   - run: the number of runs as a varint followed by the first and last element of each run, 16 bits each
+func (r *Roaring) MarshalBinary() ([]byte, error) {
+	data := []byte{roaringFormatVersion}
+	data = binenc.AppendUvarint(data, uint64(len(r.keys)))
+
+	for idx, c := range r.containers {
+		data = binenc.AppendUint16(data, r.keys[idx])
+
+		switch c := c.(type) {
+		case *arrayContainer:
+			data = append(data, arrayContainerType)
+			data = binenc.AppendUvarint(data, uint64(len(c.values)))
+			for _, v := range c.values {
+				data = binenc.AppendUint16(data, v)
+			}
+		case *bitmapContainer:
+			data = append(data, bitmapContainerType)
+			for _, w := range c.words {
+				data = binenc.AppendUint64(data, w)
+			}
+		case *runContainer:
+			data = append(data, runContainerType)
+			data = binenc.AppendUvarint(data, uint64(len(c.runs)))
+			for _, run := range c.runs {
+				data = binenc.AppendUint16(data, run.start)
+				data = binenc.AppendUint16(data, run.last)
+			}
+		}
+	}
+
+	return data, nil
+}
+
+// UnmarshalBinary decodes a set written by MarshalBinary.
+func (r *Roaring) UnmarshalBinary(data []byte) error {
+	reader := binenc.NewReader(data, errInvalidRoaring)
+
+	if version := reader.Byte(); version != roaringFormatVersion {
+		return errInvalidRoaring
+	}
+
+	count := reader.Uvarint()
+	if count > 1<<16 {
+		return errInvalidRoaring
+	}
+
+	result := NewRoaring()
+	for ; count > 0 && reader.Err() == nil; count-- {
+		key := reader.Uint16()
+		if len(result.keys) > 0 && result.keys[len(result.keys)-1] >= key {
+			return errInvalidRoaring
+		}
+
+		var c container
+		switch reader.Byte() {
+		case arrayContainerType:
+			size := reader.Uvarint()
+			if size > arrayMaxSize {
+				return errInvalidRoaring
+			}
+			values := make([]uint16, size)
+			for idx := range values {
+				values[idx] = reader.Uint16()
+				if idx > 0 && values[idx-1] >= values[idx] {
+					return errInvalidRoaring
+				}
+			}
+			c = &arrayContainer{values}
+		case bitmapContainerType:
+			b := &bitmapContainer{}
+			for idx := range b.words {
+				b.words[idx] = reader.Uint64()
+			}
+			b.computeCardinality()
+			c = b
+		case runContainerType:
+			size := reader.Uvarint()
+			if size > 1<<15 {
+				return errInvalidRoaring
+			}
+			runs := make([]interval, size)
+			for idx := range runs {
+				runs[idx] = interval{reader.Uint16(), reader.Uint16()}
+				if runs[idx].start > runs[idx].last || idx > 0 && int(runs[idx-1].last)+1 >= int(runs[idx].start) {
+					return errInvalidRoaring
+				}
+			}
+			c = &runContainer{runs}
+		default:
+			return errInvalidRoaring
+		}
+
+		if reader.Err() == nil && c.cardinality() > 0 {
+			result.keys = append(result.keys, key)
+			result.containers = append(result.containers, c)
+		}
+	}
+
+	if err := reader.Finish(); err != nil {
+		return err
+	}
+
+	*r = *result
+
+	return nil
+}
@@ -0,0 +1,502 @@
+package sets
+
+import (
+	"math/bits"
+	"sort"
+)
+
+const (
+	arrayMaxSize = 4096
+	bitmapWords  = 1 << 16 / wordSize
+	bitmapBytes  = bitmapWords * 8
+)
+
+// container keeps the low 16 bits of the elements of a Roaring bitmap that share the same high 16 bits.
+// Mutating operations may return a different container when another representation is needed.
+type container interface {
+	add(v uint16) container
+	remove(v uint16) container
+	contains(v uint16) bool
+	cardinality() int
+	// rank returns the number of elements less than or equal to v
+	rank(v uint16) int
+	// selectAt returns the n-th element in ascending order, n must be less than the cardinality
+	selectAt(n int) uint16
+	// iterate calls fn with the elements in ascending order, stopping and returning false if fn returns false
+	iterate(fn func(uint16) bool) bool
+	numberOfRuns() int
+	clone() container
+}
+
+// arrayContainer is used for sparse containers, it keeps the elements sorted
+type arrayContainer struct {
+	values []uint16
+}
+
+func (a *arrayContainer) search(v uint16) int {
+	return sort.Search(len(a.values), func(i int) bool { return a.values[i] >= v })
+}
+
+func (a *arrayContainer) add(v uint16) container {
+	idx := a.search(v)
+	if idx < len(a.values) && a.values[idx] == v {
+		return a
+	}
+	if len(a.values) == arrayMaxSize {
+		b := toBitmap(a)
+		b.add(v)
+		return b
+	}
+	a.values = append(a.values, 0)
+	copy(a.values[idx+1:], a.values[idx:])
+	a.values[idx] = v
+	return a
+}
+
+func (a *arrayContainer) remove(v uint16) container {
+	idx := a.search(v)
+	if idx < len(a.values) && a.values[idx] == v {
+		a.values = append(a.values[:idx], a.values[idx+1:]...)
+	}
+	return a
+}
+
+func (a *arrayContainer) contains(v uint16) bool {
+	idx := a.search(v)
+	return idx < len(a.values) && a.values[idx] == v
+}
+
+func (a *arrayContainer) cardinality() int {
+	return len(a.values)
+}
+
+func (a *arrayContainer) rank(v uint16) int {
+	return sort.Search(len(a.values), func(i int) bool { return a.values[i] > v })
+}
+
+func (a *arrayContainer) selectAt(n int) uint16 {
+	return a.values[n]
+}
+
+func (a *arrayContainer) iterate(fn func(uint16) bool) bool {
+	for _, v := range a.values {
+		if !fn(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *arrayContainer) numberOfRuns() int {
+	runs := 0
+	for idx, v := range a.values {
+		if idx == 0 || a.values[idx-1]+1 != v {
+			runs++
+		}
+	}
+	return runs
+}
+
+func (a *arrayContainer) clone() container {
+	values := make([]uint16, len(a.values))
+	copy(values, a.values)
+	return &arrayContainer{values}
+}
+
+// bitmapContainer is used for dense containers, it has a bit for each possible element
+type bitmapContainer struct {
+	words [bitmapWords]uint64
+	card  int
+}
+
+func (b *bitmapContainer) add(v uint16) container {
+	mask := uint64(1) << (v % wordSize)
+	if b.words[v/wordSize]&mask == 0 {
+		b.words[v/wordSize] |= mask
+		b.card++
+	}
+	return b
+}
+
+func (b *bitmapContainer) remove(v uint16) container {
+	mask := uint64(1) << (v % wordSize)
+	if b.words[v/wordSize]&mask == 0 {
+		return b
+	}
+	b.words[v/wordSize] &^= mask
+	b.card--
+	if b.card <= arrayMaxSize {
+		return toArray(b)
+	}
+	return b
+}
+
+func (b *bitmapContainer) contains(v uint16) bool {
+	return b.words[v/wordSize]&(1<<(v%wordSize)) != 0
+}
+
+func (b *bitmapContainer) cardinality() int {
+	return b.card
+}
+
+func (b *bitmapContainer) rank(v uint16) int {
+	count := 0
+	for _, w := range b.words[:v/wordSize] {
+		count += bits.OnesCount64(w)
+	}
+	// 2<<63 overflows to 0, so the mask of the last bit is all ones
+	mask := uint64(2)<<(v%wordSize) - 1
+	return count + bits.OnesCount64(b.words[v/wordSize]&mask)
+}
+
+func (b *bitmapContainer) selectAt(n int) uint16 {
+	for idx, w := range b.words {
+		count := bits.OnesCount64(w)
+		if n >= count {
+			n -= count
+			continue
+		}
+		for ; n > 0; n-- {
+			w &= w - 1
+		}
+		return uint16(idx*wordSize + bits.TrailingZeros64(w))
+	}
+	panic("select out of range")
+}
+
+func (b *bitmapContainer) iterate(fn func(uint16) bool) bool {
+	for idx, w := range b.words {
+		for w != 0 {
+			bit := bits.TrailingZeros64(w)
+			if !fn(uint16(idx*wordSize + bit)) {
+				return false
+			}
+			w &= w - 1
+		}
+	}
+	return true
+}
+
+func (b *bitmapContainer) numberOfRuns() int {
+	runs := 0
+	var previous uint64
+	for _, w := range b.words {
+		// a run starts at each set bit whose previous bit is not set
+		runs += bits.OnesCount64(w &^ (w<<1 | previous>>63))
+		previous = w
+	}
+	return runs
+}
+
+func (b *bitmapContainer) clone() container {
+	c := *b
+	return &c
+}
+
+func (b *bitmapContainer) computeCardinality() {
+	b.card = 0
+	for _, w := range b.words {
+		b.card += bits.OnesCount64(w)
+	}
+}
+
+// interval is a run of consecutive elements, from start to last both included
+type interval struct {
+	start, last uint16
+}
+
+// runContainer is used for containers with long runs of consecutive elements
+type runContainer struct {
+	runs []interval
+}
+
+// search returns the position of the first run that ends at v or after it
+func (r *runContainer) search(v uint16) int {
+	return sort.Search(len(r.runs), func(i int) bool { return r.runs[i].last >= v })
+}
+
+func (r *runContainer) add(v uint16) container {
+	idx := r.search(v)
+	if idx < len(r.runs) && r.runs[idx].start <= v {
+		return r
+	}
+
+	extendsPrevious := idx > 0 && int(r.runs[idx-1].last)+1 == int(v)
+	extendsNext := idx < len(r.runs) && int(r.runs[idx].start) == int(v)+1
+
+	switch {
+	case extendsPrevious && extendsNext:
+		r.runs[idx-1].last = r.runs[idx].last
+		r.runs = append(r.runs[:idx], r.runs[idx+1:]...)
+	case extendsPrevious:
+		r.runs[idx-1].last = v
+	case extendsNext:
+		r.runs[idx].start = v
+	default:
+		r.runs = append(r.runs, interval{})
+		copy(r.runs[idx+1:], r.runs[idx:])
+		r.runs[idx] = interval{v, v}
+	}
+
+	if 4*len(r.runs) > bitmapBytes {
+		return toBitmap(r)
+	}
+	return r
+}
+
+func (r *runContainer) remove(v uint16) container {
+	idx := r.search(v)
+	if idx == len(r.runs) || r.runs[idx].start > v {
+		return r
+	}
+
+	run := r.runs[idx]
+	switch {
+	case run.start == run.last:
+		r.runs = append(r.runs[:idx], r.runs[idx+1:]...)
+	case run.start == v:
+		r.runs[idx].start++
+	case run.last == v:
+		r.runs[idx].last--
+	default:
+		r.runs = append(r.runs, interval{})
+		copy(r.runs[idx+1:], r.runs[idx:])
+		r.runs[idx] = interval{run.start, v - 1}
+		r.runs[idx+1] = interval{v + 1, run.last}
+	}
+	return r
+}
+
+func (r *runContainer) contains(v uint16) bool {
+	idx := r.search(v)
+	return idx < len(r.runs) && r.runs[idx].start <= v
+}
+
+func (r *runContainer) cardinality() int {
+	card := 0
+	for _, run := range r.runs {
+		card += int(run.last) - int(run.start) + 1
+	}
+	return card
+}
+
+func (r *runContainer) rank(v uint16) int {
+	count := 0
+	for _, run := range r.runs {
+		if run.start > v {
+			break
+		}
+		if run.last >= v {
+			return count + int(v) - int(run.start) + 1
+		}
+		count += int(run.last) - int(run.start) + 1
+	}
+	return count
+}
+
+func (r *runContainer) selectAt(n int) uint16 {
+	for _, run := range r.runs {
+		size := int(run.last) - int(run.start) + 1
+		if n < size {
+			return run.start + uint16(n)
+		}
+		n -= size
+	}
+	panic("select out of range")
+}
+
+func (r *runContainer) iterate(fn func(uint16) bool) bool {
+	for _, run := range r.runs {
+		for v := int(run.start); v <= int(run.last); v++ {
+			if !fn(uint16(v)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (r *runContainer) numberOfRuns() int {
+	return len(r.runs)
+}
+
+func (r *runContainer) clone() container {
+	runs := make([]interval, len(r.runs))
+	copy(runs, r.runs)
+	return &runContainer{runs}
+}
+
+func toArray(c container) *arrayContainer {
+	if a, ok := c.(*arrayContainer); ok {
+		return a
+	}
+	values := make([]uint16, 0, c.cardinality())
+	c.iterate(func(v uint16) bool {
+		values = append(values, v)
+		return true
+	})
+	return &arrayContainer{values}
+}
+
+func toBitmap(c container) *bitmapContainer {
+	if b, ok := c.(*bitmapContainer); ok {
+		return b
+	}
+	b := &bitmapContainer{}
+	c.iterate(func(v uint16) bool {
+		b.words[v/wordSize] |= 1 << (v % wordSize)
+		return true
+	})
+	b.card = c.cardinality()
+	return b
+}
+
+func toRun(c container) *runContainer {
+	if r, ok := c.(*runContainer); ok {
+		return r
+	}
+	runs := make([]interval, 0, c.numberOfRuns())
+	c.iterate(func(v uint16) bool {
+		if last := len(runs) - 1; last >= 0 && int(runs[last].last)+1 == int(v) {
+			runs[last].last = v
+		} else {
+			runs = append(runs, interval{v, v})
+		}
+		return true
+	})
+	return &runContainer{runs}
+}
+
+// optimize returns the container in the representation that uses less memory, or nil if it is empty
+func optimize(c container) container {
+	card := c.cardinality()
+	if card == 0 {
+		return nil
+	}
+
+	arraySize := bitmapBytes + 1
+	if card <= arrayMaxSize {
+		arraySize = 2 * card
+	}
+	runSize := 4 * c.numberOfRuns()
+
+	switch {
+	case runSize < arraySize && runSize < bitmapBytes:
+		return toRun(c)
+	case card <= arrayMaxSize:
+		return toArray(c)
+	default:
+		return toBitmap(c)
+	}
+}
+
+// freshBitmap returns a bitmap with the elements of the container that does not share memory with it
+func freshBitmap(c container) *bitmapContainer {
+	if b, ok := c.(*bitmapContainer); ok {
+		return b.clone().(*bitmapContainer)
+	}
+	return toBitmap(c)
+}
+
+func unionContainers(a, b container) container {
+	if x, ok := a.(*arrayContainer); ok {
+		if y, ok := b.(*arrayContainer); ok && len(x.values)+len(y.values) <= arrayMaxSize {
+			return optimize(mergeArrays(x.values, y.values, true, true, true))
+		}
+	}
+	result := freshBitmap(a)
+	other := toBitmap(b)
+	for idx := range result.words {
+		result.words[idx] |= other.words[idx]
+	}
+	result.computeCardinality()
+	return optimize(result)
+}
+
+func intersectContainers(a, b container) container {
+	if x, ok := a.(*arrayContainer); ok {
+		return optimize(filterArray(x, b, true))
+	}
+	if y, ok := b.(*arrayContainer); ok {
+		return optimize(filterArray(y, a, true))
+	}
+	result := freshBitmap(a)
+	other := toBitmap(b)
+	for idx := range result.words {
+		result.words[idx] &= other.words[idx]
+	}
+	result.computeCardinality()
+	return optimize(result)
+}
+
+func differenceContainers(a, b container) container {
+	if x, ok := a.(*arrayContainer); ok {
+		return optimize(filterArray(x, b, false))
+	}
+	result := freshBitmap(a)
+	other := toBitmap(b)
+	for idx := range result.words {
+		result.words[idx] &^= other.words[idx]
+	}
+	result.computeCardinality()
+	return optimize(result)
+}
+
+func symmetricDifferenceContainers(a, b container) container {
+	if x, ok := a.(*arrayContainer); ok {
+		if y, ok := b.(*arrayContainer); ok {
+			return optimize(mergeArrays(x.values, y.values, true, false, true))
+		}
+	}
+	result := freshBitmap(a)
+	other := toBitmap(b)
+	for idx := range result.words {
+		result.words[idx] ^= other.words[idx]
+	}
+	result.computeCardinality()
+	return optimize(result)
+}
+
+// mergeArrays merges two sorted arrays keeping the elements only in x, in both or only in y as requested
+func mergeArrays(x, y []uint16, onlyX, both, onlyY bool) *arrayContainer {
+	values := make([]uint16, 0, len(x)+len(y))
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] < y[j]:
+			if onlyX {
+				values = append(values, x[i])
+			}
+			i++
+		case x[i] > y[j]:
+			if onlyY {
+				values = append(values, y[j])
+			}
+			j++
+		default:
+			if both {
+				values = append(values, x[i])
+			}
+			i++
+			j++
+		}
+	}
+	if onlyX {
+		values = append(values, x[i:]...)
+	}
+	if onlyY {
+		values = append(values, y[j:]...)
+	}
+	return &arrayContainer{values}
+}
+
+// filterArray returns the elements of the array that are (or are not) in the other container
+func filterArray(a *arrayContainer, other container, in bool) *arrayContainer {
+	values := make([]uint16, 0, len(a.values))
+	for _, v := range a.values {
+		if other.contains(v) == in {
+			values = append(values, v)
+		}
+	}
+	return &arrayContainer{values}
+}
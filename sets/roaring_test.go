@@ -0,0 +1,140 @@
+package sets
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sortedOf(s Set[uint32]) []uint32 {
+	values := s.Values()
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	return values
+}
+
+// randomRoaring creates a set with sparse, dense and consecutive regions, so all the containers are used
+func randomRoaring(random *rand.Rand) (*Roaring, Set[uint32]) {
+	r := NewRoaring()
+	s := New[uint32]()
+
+	for i := 0; i < 3000; i++ {
+		v := uint32(random.Intn(1 << 20))
+		r.Add(v)
+		s.Add(v)
+	}
+	for i := 0; i < 10000; i++ {
+		v := uint32(1<<20 + random.Intn(1<<14))
+		r.Add(v)
+		s.Add(v)
+	}
+	start := uint32(2<<20 + random.Intn(1000))
+	r.AddRange(start, start+100000)
+	for v := start; v <= start+100000; v++ {
+		s.Add(v)
+	}
+	r.Optimize()
+
+	return r, s
+}
+
+func TestRoaring_behaves_like_set(t *testing.T) {
+	random := rand.New(rand.NewSource(7))
+	a, sa := randomRoaring(random)
+	b, sb := randomRoaring(random)
+
+	assert.Equal(t, sortedOf(sa), a.Values())
+	assert.Equal(t, len(sa), a.Cardinality())
+	assert.Equal(t, sortedOf(sa.Union(sb)), a.Union(b).Values())
+	assert.Equal(t, sortedOf(sa.Intersection(sb)), a.Intersection(b).Values())
+	assert.Equal(t, sortedOf(sa.Difference(sb)), a.Difference(b).Values())
+	assert.Equal(t, sortedOf(sa.SymmetricDifference(sb)), a.SymmetricDifference(b).Values())
+	assert.True(t, a.Intersection(b).IsSubset(a))
+	assert.True(t, a.Union(b).IsSuperset(b))
+	assert.False(t, a.IsSubset(b))
+}
+
+func TestRoaring_Add_Remove_Contains(t *testing.T) {
+	r := RoaringOf(1, 70000, math.MaxUint32)
+	r.Remove(70000)
+	r.Remove(12345)
+
+	assert.True(t, r.Contains(1))
+	assert.False(t, r.Contains(70000))
+	assert.True(t, r.Contains(math.MaxUint32))
+	assert.Equal(t, "{1 4294967295 }", r.String())
+
+	r.Remove(1)
+	r.Remove(math.MaxUint32)
+	assert.True(t, r.IsEmpty())
+}
+
+func TestRoaring_containers_change_with_density(t *testing.T) {
+	r := NewRoaring()
+	for v := uint32(0); v < 10000; v += 2 {
+		r.Add(v)
+	}
+	assert.IsType(t, &bitmapContainer{}, r.containers[0])
+
+	for v := uint32(0); v < 2000; v += 2 {
+		r.Remove(v)
+	}
+	assert.IsType(t, &arrayContainer{}, r.containers[0])
+
+	r.AddRange(0, 50000)
+	assert.IsType(t, &runContainer{}, r.containers[0])
+	assert.Equal(t, 50001, r.Cardinality())
+
+	r.Remove(100)
+	assert.False(t, r.Contains(100))
+	assert.Equal(t, 50000, r.Cardinality())
+}
+
+func TestRoaring_Rank_and_Select(t *testing.T) {
+	random := rand.New(rand.NewSource(11))
+	r, _ := randomRoaring(random)
+	values := r.Values()
+
+	for _, n := range []int{0, 1, 2999, 5000, len(values) - 1} {
+		v, found := r.Select(n)
+		assert.True(t, found)
+		assert.Equal(t, values[n], v)
+		assert.Equal(t, n+1, r.Rank(v))
+	}
+
+	_, found := r.Select(len(values))
+	assert.False(t, found)
+	assert.Equal(t, 0, RoaringOf(5).Rank(4))
+	assert.Equal(t, len(values), r.Rank(math.MaxUint32))
+}
+
+func TestRoaring_Equal_and_Copy(t *testing.T) {
+	a := RoaringOf(1, 2, 3)
+	b := NewRoaring()
+	b.AddRange(1, 3)
+
+	copied := a.Copy()
+	copied.Add(4)
+
+	assert.True(t, a.Equal(b))
+	assert.False(t, a.Equal(copied))
+	assert.Equal(t, 3, a.Cardinality())
+}
+
+func TestRoaring_binary_encoding(t *testing.T) {
+	random := rand.New(rand.NewSource(3))
+	r, _ := randomRoaring(random)
+
+	data, err := r.MarshalBinary()
+	assert.Nil(t, err)
+
+	var read Roaring
+	assert.Nil(t, read.UnmarshalBinary(data))
+	assert.True(t, r.Equal(&read))
+
+	assert.Error(t, read.UnmarshalBinary(data[:len(data)-1]))
+	assert.Error(t, read.UnmarshalBinary([]byte{9}))
+	assert.Error(t, read.UnmarshalBinary([]byte{1, 1, 0, 0, 0, 2, 2, 0, 1, 0}))
+}
@@ -174,3 +174,17 @@ func (s Set[T]) String() string {
 func (s Set[T]) GoString() string {
 	return s.String()
 }
+
+// Range calls fn with each element of the set, in no particular order. Iteration stops if fn returns false.
+func (s Set[T]) Range(fn func(T) bool) {
+	for v := range s {
+		if !fn(v) {
+			return
+		}
+	}
+}
+
+// ReadOnly returns a view of the set that does not allow to modify it. Changes to the set are seen in the view.
+func (s Set[T]) ReadOnly() ReadOnly[T] {
+	return ReadOnly[T]{s}
+}
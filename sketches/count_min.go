@@ -0,0 +1,142 @@
+package sketches
+
+import (
+	"math"
+
+	"github.com/totemcaf/gollections/hashes"
+	"github.com/totemcaf/gollections/internal/binenc"
+)
+
+// CountMinSketch estimates how many times each value was added. Estimates are never below the real count, and
+// with probability 1 - delta they exceed it by at most epsilon times the total count of all values.
+type CountMinSketch[T comparable] struct {
+	counts []uint64 // depth rows of width counters
+	width  uint64
+	depth  uint64
+	total  uint64
+	hasher hashes.Hasher[T]
+}
+
+// NewCountMinSketch creates a sketch with the given error bounds, both between 0 and 1.
+// It uses e/epsilon * ln(1/delta) counters. If hasher is nil, hashes.For is used.
+func NewCountMinSketch[T comparable](epsilon, delta float64, hasher hashes.Hasher[T]) *CountMinSketch[T] {
+	if epsilon <= 0 || epsilon >= 1 || delta <= 0 || delta >= 1 {
+		panic("epsilon and delta must be between 0 and 1")
+	}
+
+	s := &CountMinSketch[T]{hasher: hasher}
+	s.init(uint64(math.Ceil(math.E/epsilon)), uint64(math.Ceil(math.Log(1/delta))))
+	return s
+}
+
+func (s *CountMinSketch[T]) init(width, depth uint64) {
+	s.counts = make([]uint64, width*depth)
+	s.width = width
+	s.depth = depth
+	s.total = 0
+	if s.hasher == nil {
+		s.hasher = hashes.For[T]()
+	}
+}
+
+// cells calls fn with the position of the counter of the value in each row
+func (s *CountMinSketch[T]) cells(value T, fn func(cell uint64)) {
+	h1 := s.hasher(value)
+	h2 := hashes.Mix(h1) | 1
+
+	for row := uint64(0); row < s.depth; row++ {
+		fn(row*s.width + (h1+row*h2)%s.width)
+	}
+}
+
+// Add counts one occurrence of the value
+func (s *CountMinSketch[T]) Add(value T) {
+	s.AddN(value, 1)
+}
+
+// AddN counts n occurrences of the value
+func (s *CountMinSketch[T]) AddN(value T, n uint64) {
+	s.cells(value, func(cell uint64) {
+		s.counts[cell] += n
+	})
+	s.total += n
+}
+
+// Estimate returns the estimated number of occurrences of the value
+func (s *CountMinSketch[T]) Estimate(value T) uint64 {
+	estimate := uint64(math.MaxUint64)
+	s.cells(value, func(cell uint64) {
+		if s.counts[cell] < estimate {
+			estimate = s.counts[cell]
+		}
+	})
+	return estimate
+}
+
+// Total returns the number of occurrences of all the values
+func (s *CountMinSketch[T]) Total() uint64 {
+	return s.total
+}
+
+// Merge adds the counts of the other sketch to this one. Both sketches must have been created with the same
+// error bounds and hasher.
+func (s *CountMinSketch[T]) Merge(other *CountMinSketch[T]) error {
+	if s.width != other.width || s.depth != other.depth {
+		return errIncompatibleSketches
+	}
+	for idx, c := range other.counts {
+		s.counts[idx] += c
+	}
+	s.total += other.total
+	return nil
+}
+
+// Clear removes all the counts
+func (s *CountMinSketch[T]) Clear() {
+	for idx := range s.counts {
+		s.counts[idx] = 0
+	}
+	s.total = 0
+}
+
+// MarshalBinary encodes the sketch as a version byte, the width, depth and total as varints and the counters as
+// varints. The hasher is not encoded, the sketch must be read with the same one.
+func (s *CountMinSketch[T]) MarshalBinary() ([]byte, error) {
+	data := []byte{formatVersion}
+	data = binenc.AppendUvarint(data, s.width)
+	data = binenc.AppendUvarint(data, s.depth)
+	data = binenc.AppendUvarint(data, s.total)
+	for _, c := range s.counts {
+		data = binenc.AppendUvarint(data, c)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes a sketch written by MarshalBinary. If the sketch has no hasher, hashes.For is used.
+func (s *CountMinSketch[T]) UnmarshalBinary(data []byte) error {
+	reader := binenc.NewReader(data, errInvalidEncoding)
+
+	version := reader.Byte()
+	width := reader.Uvarint()
+	depth := reader.Uvarint()
+	total := reader.Uvarint()
+	// each counter takes at least a byte
+	if reader.Err() != nil || version != formatVersion || width == 0 || depth == 0 ||
+		width > uint64(len(data)) || depth > uint64(len(data)) || width*depth > uint64(len(data)) {
+		return errInvalidEncoding
+	}
+
+	counts := make([]uint64, width*depth)
+	for idx := range counts {
+		counts[idx] = reader.Uvarint()
+	}
+	if err := reader.Finish(); err != nil {
+		return err
+	}
+
+	s.init(width, depth)
+	s.counts = counts
+	s.total = total
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package sketches
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// zipf returns a stream of values where a few are very frequent, like the keys of real traffic
+func zipf(seed int64, size int) []uint64 {
+	z := rand.NewZipf(rand.New(rand.NewSource(seed)), 1.2, 1, 100000)
+	values := make([]uint64, size)
+	for idx := range values {
+		values[idx] = z.Uint64()
+	}
+	return values
+}
+
+func TestCountMinSketch_Estimate_is_close_to_exact_count(t *testing.T) {
+	s := NewCountMinSketch[uint64](0.001, 0.01, nil)
+	exact := make(map[uint64]uint64)
+
+	stream := zipf(1, 200000)
+	for _, v := range stream {
+		s.Add(v)
+		exact[v]++
+	}
+
+	bound := uint64(0.001 * float64(len(stream)))
+	exceeded := 0
+	for v, count := range exact {
+		estimate := s.Estimate(v)
+		assert.GreaterOrEqual(t, estimate, count)
+		if estimate > count+bound {
+			exceeded++
+		}
+	}
+
+	assert.LessOrEqual(t, float64(exceeded)/float64(len(exact)), 0.01)
+	assert.Equal(t, uint64(len(stream)), s.Total())
+}
+
+func TestCountMinSketch_Merge_and_encoding(t *testing.T) {
+	a := NewCountMinSketch[string](0.01, 0.01, nil)
+	b := NewCountMinSketch[string](0.01, 0.01, nil)
+	a.AddN("x", 3)
+	b.AddN("x", 4)
+	b.Add("y")
+
+	assert.Nil(t, a.Merge(b))
+	assert.Equal(t, uint64(7), a.Estimate("x"))
+	assert.Equal(t, uint64(8), a.Total())
+	assert.Error(t, a.Merge(NewCountMinSketch[string](0.1, 0.01, nil)))
+
+	data, err := a.MarshalBinary()
+	assert.Nil(t, err)
+
+	var read CountMinSketch[string]
+	assert.Nil(t, read.UnmarshalBinary(data))
+	assert.Equal(t, uint64(7), read.Estimate("x"))
+	assert.Equal(t, uint64(8), read.Total())
+	assert.Error(t, read.UnmarshalBinary(data[:len(data)-1]))
+}
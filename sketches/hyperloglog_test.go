@@ -0,0 +1,70 @@
+package sketches
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/totemcaf/gollections/sets"
+)
+
+func assertWithinError(t *testing.T, exact int, estimate uint64, precision int) {
+	// 3 standard errors
+	tolerance := 3 * 1.04 / math.Sqrt(float64(int(1)<<precision))
+	assert.InEpsilon(t, float64(exact), float64(estimate), tolerance)
+}
+
+func TestHyperLogLog_Count_is_close_to_exact_count(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	for _, distinct := range []int{100, 10000, 500000} {
+		h := NewHyperLogLog[int](14, nil)
+		exact := sets.New[int]()
+
+		for i := 0; i < distinct*2; i++ {
+			v := random.Intn(distinct * 4)
+			h.Add(v)
+			exact.Add(v)
+		}
+
+		assertWithinError(t, exact.Size(), h.Count(), 14)
+	}
+}
+
+func TestHyperLogLog_Merge_counts_the_union(t *testing.T) {
+	a := NewHyperLogLog[string](12, nil)
+	b := NewHyperLogLog[string](12, nil)
+	exact := sets.New[string]()
+
+	for i := 0; i < 20000; i++ {
+		v := string(rune('a'+i%26)) + string(rune(i))
+		if i%3 == 0 {
+			a.Add(v)
+		} else {
+			b.Add(v)
+		}
+		exact.Add(v)
+	}
+
+	assert.Nil(t, a.Merge(b))
+	assertWithinError(t, exact.Size(), a.Count(), 12)
+	assert.Error(t, a.Merge(NewHyperLogLog[string](10, nil)))
+}
+
+func TestHyperLogLog_binary_encoding(t *testing.T) {
+	h := NewHyperLogLog[int](10, nil)
+	for i := 0; i < 1000; i++ {
+		h.Add(i)
+	}
+
+	data, err := h.MarshalBinary()
+	assert.Nil(t, err)
+	assert.Len(t, data, 2+1024)
+
+	var read HyperLogLog[int]
+	assert.Nil(t, read.UnmarshalBinary(data))
+	assert.Equal(t, h.Count(), read.Count())
+
+	assert.Error(t, read.UnmarshalBinary(data[:100]))
+}
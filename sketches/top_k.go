@@ -0,0 +1,177 @@
+package sketches
+
+import (
+	"bytes"
+	"encoding/gob"
+	"sort"
+
+	"github.com/totemcaf/gollections/hashes"
+	"github.com/totemcaf/gollections/internal/binenc"
+)
+
+// Frequency is a value and its estimated number of occurrences
+type Frequency[T any] struct {
+	Value T
+	Count uint64
+}
+
+// TopK finds the k most frequent values (the heavy hitters) of a stream. It counts all the values with a
+// CountMinSketch and only remembers the k values with the highest estimates.
+type TopK[T comparable] struct {
+	sketch *CountMinSketch[T]
+	k      int
+	top    map[T]uint64
+}
+
+// NewTopK creates a heavy hitters tracker of k values, with a CountMinSketch created with epsilon, delta and hasher.
+func NewTopK[T comparable](k int, epsilon, delta float64, hasher hashes.Hasher[T]) *TopK[T] {
+	if k < 1 {
+		panic("k must be positive")
+	}
+	return &TopK[T]{
+		sketch: NewCountMinSketch[T](epsilon, delta, hasher),
+		k:      k,
+		top:    make(map[T]uint64, k+1),
+	}
+}
+
+// Add counts one occurrence of the value
+func (t *TopK[T]) Add(value T) {
+	t.AddN(value, 1)
+}
+
+// AddN counts n occurrences of the value
+func (t *TopK[T]) AddN(value T, n uint64) {
+	t.sketch.AddN(value, n)
+	t.track(value, t.sketch.Estimate(value))
+}
+
+// track keeps the value if it is one of the k with higher estimates
+func (t *TopK[T]) track(value T, estimate uint64) {
+	if _, found := t.top[value]; found || len(t.top) < t.k {
+		t.top[value] = estimate
+		return
+	}
+
+	var smallest T
+	smallestCount := uint64(0)
+	first := true
+	for v, c := range t.top {
+		if first || c < smallestCount {
+			smallest, smallestCount, first = v, c, false
+		}
+	}
+
+	if estimate > smallestCount {
+		delete(t.top, smallest)
+		t.top[value] = estimate
+	}
+}
+
+// Estimate returns the estimated number of occurrences of the value
+func (t *TopK[T]) Estimate(value T) uint64 {
+	return t.sketch.Estimate(value)
+}
+
+// Total returns the number of occurrences of all the values
+func (t *TopK[T]) Total() uint64 {
+	return t.sketch.Total()
+}
+
+// Top returns the tracked values from the most to the least frequent. Order of values with the same count is not
+// defined.
+func (t *TopK[T]) Top() []Frequency[T] {
+	top := make([]Frequency[T], 0, len(t.top))
+	for v := range t.top {
+		top = append(top, Frequency[T]{v, t.sketch.Estimate(v)})
+	}
+	sort.Slice(top, func(i, j int) bool { return top[i].Count > top[j].Count })
+	return top
+}
+
+// Merge adds the counts of the other tracker to this one. Both trackers must have been created with the same
+// parameters. The heavy hitters of the result are chosen among the ones of both trackers.
+func (t *TopK[T]) Merge(other *TopK[T]) error {
+	if t.k != other.k {
+		return errIncompatibleSketches
+	}
+	if err := t.sketch.Merge(other.sketch); err != nil {
+		return err
+	}
+
+	candidates := make([]T, 0, len(t.top)+len(other.top))
+	for v := range t.top {
+		candidates = append(candidates, v)
+	}
+	for v := range other.top {
+		candidates = append(candidates, v)
+	}
+
+	t.top = make(map[T]uint64, t.k+1)
+	for _, v := range candidates {
+		t.track(v, t.sketch.Estimate(v))
+	}
+
+	return nil
+}
+
+// MarshalBinary encodes the tracker as the length of the encoded sketch as a varint, the sketch, k as a varint and
+// the tracked values encoded with gob, so T must be supported by encoding/gob.
+func (t *TopK[T]) MarshalBinary() ([]byte, error) {
+	sketch, err := t.sketch.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]T, 0, len(t.top))
+	for v := range t.top {
+		values = append(values, v)
+	}
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(values); err != nil {
+		return nil, err
+	}
+
+	data := binenc.AppendUvarint(nil, uint64(len(sketch)))
+	data = append(data, sketch...)
+	data = binenc.AppendUvarint(data, uint64(t.k))
+	return append(data, buffer.Bytes()...), nil
+}
+
+// UnmarshalBinary decodes a tracker written by MarshalBinary. If the tracker has no hasher, hashes.For is used.
+func (t *TopK[T]) UnmarshalBinary(data []byte) error {
+	reader := binenc.NewReader(data, errInvalidEncoding)
+
+	size := reader.Uvarint()
+	if size > uint64(len(data)) {
+		return errInvalidEncoding
+	}
+	encodedSketch := reader.Bytes(int(size))
+	k := reader.Uvarint()
+	if reader.Err() != nil || k == 0 || k > uint64(len(data)) {
+		return errInvalidEncoding
+	}
+
+	sketch := &CountMinSketch[T]{}
+	if t.sketch != nil {
+		sketch.hasher = t.sketch.hasher
+	}
+	if err := sketch.UnmarshalBinary(encodedSketch); err != nil {
+		return err
+	}
+
+	var values []T
+	if err := gob.NewDecoder(bytes.NewReader(reader.Bytes(reader.Remaining()))).Decode(&values); err != nil {
+		return errInvalidEncoding
+	}
+
+	t.sketch = sketch
+	t.k = int(k)
+	t.top = make(map[T]uint64, t.k+1)
+	for _, v := range values {
+		t.track(v, sketch.Estimate(v))
+	}
+
+	return nil
+}
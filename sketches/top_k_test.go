@@ -0,0 +1,46 @@
+package sketches
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopK_finds_heavy_hitters(t *testing.T) {
+	top := NewTopK[uint64](5, 0.001, 0.01, nil)
+	exact := make(map[uint64]uint64)
+
+	for _, v := range zipf(2, 200000) {
+		top.Add(v)
+		exact[v]++
+	}
+
+	// with this distribution the most frequent values are the smallest ones
+	var values []uint64
+	for _, f := range top.Top() {
+		values = append(values, f.Value)
+		assert.InEpsilon(t, exact[f.Value], f.Count, 0.01)
+	}
+	assert.Equal(t, []uint64{0, 1, 2, 3, 4}, values)
+}
+
+func TestTopK_Merge_and_encoding(t *testing.T) {
+	a := NewTopK[string](2, 0.01, 0.01, nil)
+	b := NewTopK[string](2, 0.01, 0.01, nil)
+	a.AddN("x", 5)
+	a.AddN("y", 4)
+	b.AddN("z", 6)
+	b.AddN("y", 3)
+
+	assert.Nil(t, a.Merge(b))
+	assert.Equal(t, []Frequency[string]{{"y", 7}, {"z", 6}}, a.Top())
+
+	data, err := a.MarshalBinary()
+	assert.Nil(t, err)
+
+	var read TopK[string]
+	assert.Nil(t, read.UnmarshalBinary(data))
+	assert.Equal(t, a.Top(), read.Top())
+	read.AddN("x", 10)
+	assert.Equal(t, "x", read.Top()[0].Value)
+}
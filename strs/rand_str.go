@@ -1,7 +1,10 @@
 package strs
 
 import (
+	cryptorand "crypto/rand"
+	"fmt"
 	"math/rand"
+	"sync"
 	"time"
 	"unsafe"
 )
@@ -15,8 +18,22 @@ const (
 	letterIdxMax  = 69 / letterIdxBits   // # of letter indices fitting in 63 bits
 )
 
-var src = rand.NewSource(time.Now().UnixNano())
+// src is shared by all the callers. A rand.Source is not safe for concurrent use, so it is guarded by a lock.
+var src = &lockedSource{source: rand.NewSource(time.Now().UnixNano())}
 
+type lockedSource struct {
+	lock   sync.Mutex
+	source rand.Source
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.source.Int63()
+}
+
+// RandString returns a random string of n characters. It is safe for concurrent use.
 func RandString(n int) string {
 	b := make([]byte, n)
 	// A src.Int63() generates 63 random bits, enough for letterIdxMax characters!
@@ -34,3 +51,16 @@ func RandString(n int) string {
 
 	return *(*string)(unsafe.Pointer(&b))
 }
+
+// RandUUID returns a random (version 4) UUID string like "0b7e1d5a-3c8f-4e2a-9d61-5f0c2b8a7e34"
+func RandUUID() string {
+	var b [16]byte
+	if _, err := cryptorand.Read(b[:]); err != nil {
+		panic(err)
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variant RFC 4122
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -2,6 +2,8 @@ package strs
 
 import (
 	"fmt"
+	"regexp"
+	"sync"
 	"testing"
 )
 
@@ -16,3 +18,34 @@ func TestRandString(t *testing.T) {
 		})
 	}
 }
+
+func TestRandUUID(t *testing.T) {
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	first, second := RandUUID(), RandUUID()
+
+	if !uuid.MatchString(first) {
+		t.Errorf("RandUUID() = %v, is not a UUID", first)
+	}
+	if first == second {
+		t.Errorf("RandUUID() = %v, repeated value", first)
+	}
+}
+
+func TestRandString_concurrent_use(t *testing.T) {
+	var wg sync.WaitGroup
+
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				if got := RandString(10); len(got) != 10 {
+					t.Errorf("RandString() = %v, want %v", got, 10)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,238 @@
+package syncs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/totemcaf/gollections/queues"
+)
+
+var errQueueClosed = errors.New("queue is closed")
+
+// BlockingQueue is a FIFO queue with a capacity, safe to use from many goroutines, to pass values from producers
+// to consumers. Producers wait while it is full and consumers wait while it is empty.
+//
+// In ring buffer mode (see NewRingBuffer) producers never wait: when it is full the oldest value is discarded, so
+// it keeps the last values added.
+//
+// Once closed, no more values can be added, but the ones in the queue can still be taken.
+// Create it with NewBlockingQueue or NewRingBuffer.
+type BlockingQueue[T any] struct {
+	lock      sync.Mutex
+	items     queues.Deque[T]
+	capacity  int
+	overwrite bool
+	closed    bool
+	dropped   int
+	// changed is closed and replaced each time the queue changes, to wake up the waiting goroutines
+	changed chan struct{}
+}
+
+// NewBlockingQueue creates an empty queue for at most capacity values
+func NewBlockingQueue[T any](capacity int) *BlockingQueue[T] {
+	if capacity < 1 {
+		panic("capacity must be positive")
+	}
+	return &BlockingQueue[T]{
+		items:    *queues.NewDeque[T](capacity),
+		capacity: capacity,
+		changed:  make(chan struct{}),
+	}
+}
+
+// NewRingBuffer creates an empty queue that keeps the last capacity values added, discarding the oldest ones
+func NewRingBuffer[T any](capacity int) *BlockingQueue[T] {
+	q := NewBlockingQueue[T](capacity)
+	q.overwrite = true
+	return q
+}
+
+// notify wakes up all the waiting goroutines, it must be called with the lock held
+func (q *BlockingQueue[T]) notify() {
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
+
+// wait waits, with the lock held, until ready returns true, the queue is closed or the context is done
+func (q *BlockingQueue[T]) wait(ctx context.Context, ready func() bool) error {
+	for !ready() {
+		if q.closed {
+			return errQueueClosed
+		}
+
+		changed := q.changed
+		q.lock.Unlock()
+		select {
+		case <-ctx.Done():
+			q.lock.Lock()
+			return ctx.Err()
+		case <-changed:
+			q.lock.Lock()
+		}
+	}
+	return nil
+}
+
+func (q *BlockingQueue[T]) notFull() bool {
+	return q.overwrite || q.items.Count() < q.capacity
+}
+
+func (q *BlockingQueue[T]) notEmpty() bool {
+	return !q.items.IsEmpty()
+}
+
+// Put adds the value at the back, waiting while the queue is full. It fails if the queue is closed or the context is
+// done before there is room.
+func (q *BlockingQueue[T]) Put(ctx context.Context, value T) error {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if q.closed {
+		return errQueueClosed
+	}
+	if err := q.wait(ctx, q.notFull); err != nil {
+		return err
+	}
+
+	if q.items.Count() == q.capacity {
+		q.items.PopFront()
+		q.dropped++
+	}
+	q.items.PushBack(value)
+	q.notify()
+
+	return nil
+}
+
+// Offer adds the value at the back, waiting at most timeout while the queue is full. It returns false if the value
+// was not added.
+func (q *BlockingQueue[T]) Offer(value T, timeout time.Duration) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return q.Put(ctx, value) == nil
+}
+
+// Take removes and returns the value at the front, waiting while the queue is empty. It fails if the context is
+// done or the queue is closed before there is a value.
+func (q *BlockingQueue[T]) Take(ctx context.Context) (T, error) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if err := q.wait(ctx, q.notEmpty); err != nil {
+		var empty T
+		return empty, err
+	}
+
+	value, _ := q.items.PopFront()
+	q.notify()
+
+	return value, nil
+}
+
+// Poll removes and returns the value at the front, waiting at most timeout while the queue is empty. It reports
+// false if there was no value.
+func (q *BlockingQueue[T]) Poll(timeout time.Duration) (T, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	value, err := q.Take(ctx)
+	return value, err == nil
+}
+
+// TakeBatch waits while the queue is empty and then removes and returns up to max values from the front.
+// It fails if the context is done or the queue is closed before there is a value.
+func (q *BlockingQueue[T]) TakeBatch(ctx context.Context, max int) ([]T, error) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if err := q.wait(ctx, q.notEmpty); err != nil {
+		return nil, err
+	}
+
+	return q.drain(max), nil
+}
+
+// DrainTo removes and returns up to max values from the front without waiting. If max is not positive all the
+// values are returned.
+func (q *BlockingQueue[T]) DrainTo(max int) []T {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return q.drain(max)
+}
+
+func (q *BlockingQueue[T]) drain(max int) []T {
+	if max <= 0 || max > q.items.Count() {
+		max = q.items.Count()
+	}
+
+	values := make([]T, max)
+	for idx := range values {
+		values[idx], _ = q.items.PopFront()
+	}
+	if max > 0 {
+		q.notify()
+	}
+
+	return values
+}
+
+// Peek returns the value at the front without removing it, or reports the queue is empty
+func (q *BlockingQueue[T]) Peek() (T, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return q.items.Front()
+}
+
+// Values returns the values in the queue from the front to the back without removing them
+func (q *BlockingQueue[T]) Values() []T {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return q.items.Values()
+}
+
+// Len returns the number of values in the queue
+func (q *BlockingQueue[T]) Len() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return q.items.Count()
+}
+
+// Capacity returns the maximum number of values in the queue
+func (q *BlockingQueue[T]) Capacity() int {
+	return q.capacity
+}
+
+// Dropped returns the number of values discarded to make room for new ones in ring buffer mode
+func (q *BlockingQueue[T]) Dropped() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return q.dropped
+}
+
+// Close prevents adding more values and wakes up all the waiting goroutines. Waiting producers fail, and waiting
+// consumers fail once the queue is empty. Closing more than once has no effect.
+func (q *BlockingQueue[T]) Close() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if !q.closed {
+		q.closed = true
+		q.notify()
+	}
+}
+
+// Closed returns true if the queue was closed
+func (q *BlockingQueue[T]) Closed() bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return q.closed
+}
@@ -0,0 +1,136 @@
+package syncs
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockingQueue_producers_and_consumers(t *testing.T) {
+	q := NewBlockingQueue[int](3)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for p := 0; p < 4; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				assert.Nil(t, q.Put(ctx, p*100+i))
+			}
+		}(p)
+	}
+
+	received := make(map[int]bool)
+	for len(received) < 400 {
+		v, err := q.Take(ctx)
+		assert.Nil(t, err)
+		received[v] = true
+	}
+	wg.Wait()
+
+	assert.Equal(t, 0, q.Len())
+}
+
+func TestBlockingQueue_Offer_and_Poll_time_out(t *testing.T) {
+	q := NewBlockingQueue[string](1)
+
+	assert.True(t, q.Offer("a", 0))
+	assert.False(t, q.Offer("b", 10*time.Millisecond))
+
+	v, ok := q.Poll(0)
+	assert.True(t, ok)
+	assert.Equal(t, "a", v)
+
+	_, ok = q.Poll(10 * time.Millisecond)
+	assert.False(t, ok)
+}
+
+func TestBlockingQueue_Put_waits_for_room(t *testing.T) {
+	q := NewBlockingQueue[int](1)
+	ctx := context.Background()
+	assert.Nil(t, q.Put(ctx, 1))
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		_, _ = q.Take(ctx)
+	}()
+
+	assert.Nil(t, q.Put(ctx, 2))
+	assert.Equal(t, []int{2}, q.Values())
+}
+
+func TestBlockingQueue_context_cancels_wait(t *testing.T) {
+	q := NewBlockingQueue[int](1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := q.Take(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestBlockingQueue_TakeBatch_and_DrainTo(t *testing.T) {
+	q := NewBlockingQueue[int](10)
+	for i := 1; i <= 5; i++ {
+		q.Offer(i, 0)
+	}
+
+	batch, err := q.TakeBatch(context.Background(), 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 2}, batch)
+
+	head, _ := q.Peek()
+	assert.Equal(t, 3, head)
+	assert.Equal(t, []int{3, 4, 5}, q.DrainTo(0))
+	assert.Empty(t, q.DrainTo(3))
+}
+
+func TestBlockingQueue_Close(t *testing.T) {
+	q := NewBlockingQueue[int](2)
+	ctx := context.Background()
+	assert.Nil(t, q.Put(ctx, 1))
+
+	waiting := make(chan error)
+	go func() {
+		_, err := q.TakeBatch(ctx, 10) // takes 1
+		assert.Nil(t, err)
+		_, err = q.Take(ctx) // waits until closed
+		waiting <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	q.Close()
+
+	assert.ErrorContains(t, <-waiting, "closed")
+	assert.ErrorContains(t, q.Put(ctx, 2), "closed")
+	assert.True(t, q.Closed())
+}
+
+func TestBlockingQueue_Close_keeps_values_to_take(t *testing.T) {
+	q := NewBlockingQueue[int](2)
+	q.Offer(1, 0)
+	q.Close()
+
+	v, err := q.Take(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, v)
+
+	_, err = q.Take(context.Background())
+	assert.Error(t, err)
+}
+
+func TestRingBuffer_keeps_last_values(t *testing.T) {
+	logs := NewRingBuffer[string](3)
+
+	for _, line := range []string{"l1", "l2", "l3", "l4", "l5"} {
+		assert.Nil(t, logs.Put(context.Background(), line))
+	}
+
+	assert.Equal(t, []string{"l3", "l4", "l5"}, logs.Values())
+	assert.Equal(t, 2, logs.Dropped())
+	assert.Equal(t, 3, logs.Capacity())
+}